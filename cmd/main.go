@@ -2,6 +2,7 @@ package main
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
@@ -10,12 +11,47 @@ import (
 	"github.com/ferdy-adr/elibrary-backend/internal/configs"
 	authHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/auth"
 	bookHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/books"
+	fineHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/fines"
+	holdHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/holds"
+	jobHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/jobs"
+	loanHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/loans"
+	notificationHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/notifications"
+	shelfHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/shelves"
+	tierHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/tiers"
+	userHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/users"
+	webhookHandler "github.com/ferdy-adr/elibrary-backend/internal/handlers/webhooks"
 	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	apiKeyRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/apikeys"
+	authEventRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/authevents"
 	bookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/books"
+	copyRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/copies"
+	fineRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/fines"
+	holdRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/holds"
+	identityRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/identities"
+	jobRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/jobs"
+	loanRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/loans"
+	lockoutRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/lockouts"
+	notificationRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/notifications"
+	sessionRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/sessions"
+	settingRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/settings"
+	shelfRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/shelves"
+	tierRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/tiers"
+	tokenRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/tokens"
 	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
+	webhookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/webhooks"
 	authService "github.com/ferdy-adr/elibrary-backend/internal/service/auth"
 	bookService "github.com/ferdy-adr/elibrary-backend/internal/service/books"
+	fineService "github.com/ferdy-adr/elibrary-backend/internal/service/fines"
+	holdService "github.com/ferdy-adr/elibrary-backend/internal/service/holds"
+	jobService "github.com/ferdy-adr/elibrary-backend/internal/service/jobs"
+	loanService "github.com/ferdy-adr/elibrary-backend/internal/service/loans"
+	notificationService "github.com/ferdy-adr/elibrary-backend/internal/service/notifications"
+	shelfService "github.com/ferdy-adr/elibrary-backend/internal/service/shelves"
+	tierService "github.com/ferdy-adr/elibrary-backend/internal/service/tiers"
+	userService "github.com/ferdy-adr/elibrary-backend/internal/service/users"
+	webhookService "github.com/ferdy-adr/elibrary-backend/internal/service/webhooks"
 	"github.com/ferdy-adr/elibrary-backend/pkg/internalsql"
+	"github.com/ferdy-adr/elibrary-backend/pkg/mailer"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
@@ -46,6 +82,90 @@ func convertMySQLURL(mysqlURL string) (string, error) {
 	return dsn, nil
 }
 
+// newMailer builds the mail sender selected by mail.driver
+func newMailer(cfg configs.Mail) mailer.Mailer {
+	if cfg.Driver == "smtp" {
+		return mailer.NewSMTPMailer(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From)
+	}
+	return mailer.NewOutboxMailer(cfg.OutboxPath, cfg.From)
+}
+
+// Default job schedules, used when the scheduler config leaves one empty
+const (
+	defaultDueRemindersSchedule       = "0 8 * * *"
+	defaultOverdueNoticesSchedule     = "0 9 * * *"
+	defaultExpireStaleRecordsSchedule = "*/5 * * * *"
+	defaultNotificationEmailsSchedule = "* * * * *"
+	defaultWebhookRetriesSchedule     = "* * * * *"
+)
+
+func scheduleOrDefault(spec, fallback string) string {
+	if spec == "" {
+		return fallback
+	}
+	return spec
+}
+
+// registerJobs adds the background jobs to the scheduler
+func registerJobs(
+	scheduler *jobService.Service,
+	cfg configs.Scheduler,
+	loanSvc *loanService.Service,
+	holdSvc *holdService.Service,
+	authSvc *authService.Service,
+	notificationSvc *notificationService.Service,
+	webhookSvc *webhookService.Service,
+) error {
+	err := scheduler.Register(
+		"notification_emails",
+		scheduleOrDefault(cfg.NotificationEmails, defaultNotificationEmailsSchedule),
+		notificationSvc.SendPendingEmails,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = scheduler.Register(
+		"webhook_retries",
+		scheduleOrDefault(cfg.WebhookRetries, defaultWebhookRetriesSchedule),
+		webhookSvc.RetryDueDeliveries,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = scheduler.Register(
+		"due_reminders",
+		scheduleOrDefault(cfg.DueReminders, defaultDueRemindersSchedule),
+		loanSvc.SendDueReminders,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = scheduler.Register(
+		"overdue_notices",
+		scheduleOrDefault(cfg.OverdueNotices, defaultOverdueNoticesSchedule),
+		loanSvc.SendOverdueNotices,
+	)
+	if err != nil {
+		return err
+	}
+
+	return scheduler.Register(
+		"expire_stale_records",
+		scheduleOrDefault(cfg.ExpireStaleRecords, defaultExpireStaleRecordsSchedule),
+		func() (string, error) {
+			expiredHolds, err := holdSvc.ExpireHolds()
+			if err != nil {
+				return fmt.Sprintf("%d holds expired", expiredHolds), err
+			}
+			purged, err := authSvc.PurgeExpiredRecords()
+			return fmt.Sprintf("%d holds expired, %d auth records purged", expiredHolds, purged), err
+		},
+	)
+}
+
 // runMigrations runs database migrations automatically
 func runMigrations(db *sql.DB) error {
 	driver, err := mysql.WithInstance(db, &mysql.Config{})
@@ -128,24 +248,120 @@ func main() {
 	// Initialize repositories
 	userRepository := userRepo.NewRepository(db)
 	bookRepository := bookRepo.NewRepository(db)
+	copyRepository := copyRepo.NewRepository(db)
+	tokenRepository := tokenRepo.NewRepository(db)
+	lockoutRepository := lockoutRepo.NewRepository(db)
+	settingRepository := settingRepo.NewRepository(db)
+	apiKeyRepository := apiKeyRepo.NewRepository(db)
+	identityRepository := identityRepo.NewRepository(db)
+	sessionRepository := sessionRepo.NewRepository(db)
+	authEventRepository := authEventRepo.NewRepository(db)
+	loanRepository := loanRepo.NewRepository(db)
+	holdRepository := holdRepo.NewRepository(db)
+	fineRepository := fineRepo.NewRepository(db)
+	tierRepository := tierRepo.NewRepository(db)
+	jobRepository := jobRepo.NewRepository(db)
+	notificationRepository := notificationRepo.NewRepository(db)
+	webhookRepository := webhookRepo.NewRepository(db)
+	shelfRepository := shelfRepo.NewRepository(db)
+
+	// Initialize mailer
+	mailSender := newMailer(cfg.Mail)
 
 	// Initialize services
-	authSvc := authService.NewService(userRepository)
-	bookSvc := bookService.NewService(bookRepository)
+	authSvc := authService.NewService(
+		userRepository,
+		tokenRepository,
+		lockoutRepository,
+		settingRepository,
+		apiKeyRepository,
+		identityRepository,
+		sessionRepository,
+		authEventRepository,
+		mailSender,
+	)
+	userSvc := userService.NewService(userRepository, lockoutRepository, sessionRepository, authEventRepository)
+	tierSvc := tierService.NewService(tierRepository, userRepository)
+	notificationSvc := notificationService.NewService(notificationRepository, mailSender)
+	holdSvc := holdService.NewService(holdRepository, bookRepository, loanRepository, tierSvc, notificationSvc)
+	bookSvc := bookService.NewService(bookRepository, copyRepository, holdSvc)
+	fineSvc := fineService.NewService(fineRepository, loanRepository)
+	loanSvc := loanService.NewService(
+		loanRepository,
+		copyRepository,
+		userRepository,
+		holdSvc,
+		fineSvc,
+		tierSvc,
+		notificationSvc,
+	)
+	jobSvc := jobService.NewService(jobRepository)
+	webhookSvc := webhookService.NewService(webhookRepository)
+	shelfSvc := shelfService.NewService(shelfRepository, bookRepository)
+
+	// Account export and deletion
+	authSvc.AddAccountDataOwner(loanSvc, holdSvc, fineSvc, notificationSvc, shelfSvc)
+
+	// Catalog events
+	bookSvc.OnEvent(notificationSvc.HandleBookEvent)
+	bookSvc.OnEvent(webhookSvc.HandleBookEvent)
+
+	// Load access token signing keys
+	if err := authSvc.LoadSigningKeys(cfg.JWT); err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+
+	// Make sure the configured admin account exists
+	if err := authSvc.BootstrapAdmin(cfg.Admin); err != nil {
+		log.Printf("Warning: Admin bootstrap failed: %v", err)
+	}
+
+	// Start background jobs; every replica polls, but only one runs each job
+	if err := registerJobs(jobSvc, cfg.Scheduler, loanSvc, holdSvc, authSvc, notificationSvc, webhookSvc); err != nil {
+		log.Printf("Warning: Job registration failed: %v", err)
+	} else if cfg.Scheduler.Disabled {
+		log.Println("Scheduler disabled on this replica")
+	} else {
+		go jobSvc.Run()
+	}
 
 	// Initialize handlers
 	authHdl := authHandler.NewHandler(authSvc)
-	bookHdl := bookHandler.NewHandler(bookSvc)
+	bookHdl := bookHandler.NewHandler(bookSvc, shelfSvc, authSvc)
+	userHdl := userHandler.NewHandler(userSvc, authSvc)
+	loanHdl := loanHandler.NewHandler(loanSvc, authSvc)
+	holdHdl := holdHandler.NewHandler(holdSvc, authSvc)
+	fineHdl := fineHandler.NewHandler(fineSvc, authSvc)
+	tierHdl := tierHandler.NewHandler(tierSvc, authSvc)
+	jobHdl := jobHandler.NewHandler(jobSvc, authSvc)
+	notificationHdl := notificationHandler.NewHandler(notificationSvc, authSvc)
+	webhookHdl := webhookHandler.NewHandler(webhookSvc, authSvc)
+	shelfHdl := shelfHandler.NewHandler(shelfSvc, authSvc)
 
 	// Initialize Gin router
 	r := gin.Default()
 
+	// Only trust X-Forwarded-For from known proxies, since the client IP
+	// drives login throttling and the auth audit log
+	if err := r.SetTrustedProxies(cfg.Service.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
 	// Add middleware
 	r.Use(middleware.CORSMiddleware())
 
 	// Register routes
 	authHdl.RegisterRoutes(r)
 	bookHdl.RegisterRoutes(r)
+	userHdl.RegisterRoutes(r)
+	loanHdl.RegisterRoutes(r)
+	holdHdl.RegisterRoutes(r)
+	fineHdl.RegisterRoutes(r)
+	tierHdl.RegisterRoutes(r)
+	jobHdl.RegisterRoutes(r)
+	notificationHdl.RegisterRoutes(r)
+	webhookHdl.RegisterRoutes(r)
+	shelfHdl.RegisterRoutes(r)
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -36,9 +36,26 @@ func Init(opts ...Option) error {
 	// Set environment variable mappings for Railway
 	viper.SetEnvPrefix("")
 	viper.BindEnv("service.port", "PORT")
+	viper.BindEnv("service.trustedProxies", "TRUSTED_PROXIES")
 	viper.BindEnv("database.dataSourceName", "DATABASE_URL")
 	viper.BindEnv("jwt.secretKey", "JWT_SECRET_KEY")
+	viper.BindEnv("jwt.algorithm", "JWT_ALGORITHM")
+	viper.BindEnv("jwt.keyId", "JWT_KEY_ID")
+	viper.BindEnv("jwt.privateKeyPath", "JWT_PRIVATE_KEY_PATH")
 	viper.BindEnv("upload.path", "UPLOAD_PATH")
+	viper.BindEnv("admin.username", "ADMIN_USERNAME")
+	viper.BindEnv("admin.email", "ADMIN_EMAIL")
+	viper.BindEnv("admin.password", "ADMIN_PASSWORD")
+	viper.BindEnv("auth.passwordResetURL", "PASSWORD_RESET_URL")
+	viper.BindEnv("auth.verificationURL", "EMAIL_VERIFICATION_URL")
+	viper.BindEnv("auth.twoFactorKey", "TWO_FACTOR_KEY")
+	viper.BindEnv("mail.driver", "MAIL_DRIVER")
+	viper.BindEnv("mail.host", "SMTP_HOST")
+	viper.BindEnv("mail.port", "SMTP_PORT")
+	viper.BindEnv("mail.username", "SMTP_USERNAME")
+	viper.BindEnv("mail.password", "SMTP_PASSWORD")
+	viper.BindEnv("mail.from", "MAIL_FROM")
+	viper.BindEnv("webhooks.secretKey", "WEBHOOK_SECRET_KEY")
 
 	config = new(Config)
 
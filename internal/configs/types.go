@@ -1,15 +1,30 @@
 package configs
 
+import "time"
+
 type (
 	Config struct {
-		Service  Service  `mapstructure:"service"`
-		Database Database `mapstructure:"database"`
-		JWT      JWT      `mapstructure:"jwt"`
-		Upload   Upload   `mapstructure:"upload"`
+		Service   Service   `mapstructure:"service"`
+		Database  Database  `mapstructure:"database"`
+		JWT       JWT       `mapstructure:"jwt"`
+		Upload    Upload    `mapstructure:"upload"`
+		Admin     Admin     `mapstructure:"admin"`
+		Auth      Auth      `mapstructure:"auth"`
+		Mail      Mail      `mapstructure:"mail"`
+		OIDC      OIDC      `mapstructure:"oidc"`
+		Loans     Loans     `mapstructure:"loans"`
+		Holds     Holds     `mapstructure:"holds"`
+		Fines     Fines     `mapstructure:"fines"`
+		Scheduler Scheduler `mapstructure:"scheduler"`
+		Webhooks  Webhooks  `mapstructure:"webhooks"`
 	}
 
 	Service struct {
 		Port string `mapstructure:"port"`
+
+		// TrustedProxies lists the proxy IPs or CIDRs allowed to set
+		// X-Forwarded-For. Empty means the client IP is the peer address.
+		TrustedProxies []string `mapstructure:"trustedProxies"`
 	}
 
 	Database struct {
@@ -17,10 +32,141 @@ type (
 	}
 
 	JWT struct {
-		SecretKey string `mapstructure:"secretKey"`
+		SecretKey       string        `mapstructure:"secretKey"`
+		AccessTokenTTL  time.Duration `mapstructure:"accessTokenTTL"`
+		RefreshTokenTTL time.Duration `mapstructure:"refreshTokenTTL"`
+
+		// Algorithm is HS256 (default, signs with SecretKey), RS256 or EdDSA.
+		Algorithm        string       `mapstructure:"algorithm"`
+		KeyID            string       `mapstructure:"keyId"`
+		PrivateKeyPath   string       `mapstructure:"privateKeyPath"`
+		VerificationKeys []JWTKeyFile `mapstructure:"verificationKeys"`
+	}
+
+	JWTKeyFile struct {
+		KeyID         string `mapstructure:"keyId"`
+		PublicKeyPath string `mapstructure:"publicKeyPath"`
 	}
 
 	Upload struct {
 		Path string `mapstructure:"path"`
 	}
+
+	Admin struct {
+		Username string `mapstructure:"username"`
+		Email    string `mapstructure:"email"`
+		Password string `mapstructure:"password"`
+		FullName string `mapstructure:"fullName"`
+	}
+
+	Auth struct {
+		PasswordResetTTL time.Duration `mapstructure:"passwordResetTTL"`
+		PasswordResetURL string        `mapstructure:"passwordResetURL"`
+		VerificationTTL  time.Duration `mapstructure:"verificationTTL"`
+		VerificationURL  string        `mapstructure:"verificationURL"`
+
+		MaxFailedLogins      int           `mapstructure:"maxFailedLogins"`
+		MaxFailedLoginsPerIP int           `mapstructure:"maxFailedLoginsPerIP"`
+		LockoutDuration      time.Duration `mapstructure:"lockoutDuration"`
+		LoginBackoffBase     time.Duration `mapstructure:"loginBackoffBase"`
+
+		TwoFactorIssuer       string        `mapstructure:"twoFactorIssuer"`
+		TwoFactorKey          string        `mapstructure:"twoFactorKey"`
+		TwoFactorChallengeTTL time.Duration `mapstructure:"twoFactorChallengeTTL"`
+
+		PasswordPolicy PasswordPolicy `mapstructure:"passwordPolicy"`
+	}
+
+	PasswordPolicy struct {
+		MinLength            int    `mapstructure:"minLength"`
+		RequireUppercase     bool   `mapstructure:"requireUppercase"`
+		RequireLowercase     bool   `mapstructure:"requireLowercase"`
+		RequireDigit         bool   `mapstructure:"requireDigit"`
+		RequireSymbol        bool   `mapstructure:"requireSymbol"`
+		DisableBreachedCheck bool   `mapstructure:"disableBreachedCheck"`
+		BreachedListPath     string `mapstructure:"breachedListPath"`
+	}
+
+	Mail struct {
+		Driver     string `mapstructure:"driver"`
+		Host       string `mapstructure:"host"`
+		Port       int    `mapstructure:"port"`
+		Username   string `mapstructure:"username"`
+		Password   string `mapstructure:"password"`
+		From       string `mapstructure:"from"`
+		OutboxPath string `mapstructure:"outboxPath"`
+	}
+
+	OIDC struct {
+		StateTTL  time.Duration  `mapstructure:"stateTTL"`
+		Providers []OIDCProvider `mapstructure:"providers"`
+	}
+
+	OIDCProvider struct {
+		Name         string   `mapstructure:"name"`
+		DisplayName  string   `mapstructure:"displayName"`
+		Issuer       string   `mapstructure:"issuer"`
+		ClientID     string   `mapstructure:"clientId"`
+		ClientSecret string   `mapstructure:"clientSecret"`
+		RedirectURL  string   `mapstructure:"redirectURL"`
+		Scopes       []string `mapstructure:"scopes"`
+		// LinkByEmail attaches a first-time identity to the existing account
+		// with the same email. Only enable it for providers that verify emails.
+		LinkByEmail bool `mapstructure:"linkByEmail"`
+	}
+
+	Loans struct {
+		LoanPeriod    time.Duration `mapstructure:"loanPeriod"`
+		RenewalPeriod time.Duration `mapstructure:"renewalPeriod"`
+		// MaxRenewals is how often a loan can be renewed. Zero turns
+		// renewals off; left out, it defaults to 2.
+		MaxRenewals *int `mapstructure:"maxRenewals"`
+		// RenewalOverdueLimit is how long past its due date a loan can still
+		// be renewed. Zero refuses renewals as soon as a loan is overdue;
+		// left out, it defaults to 72h.
+		RenewalOverdueLimit *time.Duration `mapstructure:"renewalOverdueLimit"`
+	}
+
+	Holds struct {
+		PickupPeriod time.Duration `mapstructure:"pickupPeriod"`
+	}
+
+	// Fines amounts are in whole rupiah. A zero DailyRate disables fines, a
+	// zero MaxAmount or BlockBalance disables the cap or the borrowing block.
+	Fines struct {
+		DailyRate    int           `mapstructure:"dailyRate"`
+		GracePeriod  time.Duration `mapstructure:"gracePeriod"`
+		MaxAmount    int           `mapstructure:"maxAmount"`
+		BlockBalance int           `mapstructure:"blockBalance"`
+	}
+
+	// Scheduler runs the background jobs. Job schedules are five-field cron
+	// expressions in server local time. Disabled stops this replica from
+	// running jobs, for example on replicas that should only serve HTTP.
+	Scheduler struct {
+		Disabled     bool          `mapstructure:"disabled"`
+		PollInterval time.Duration `mapstructure:"pollInterval"`
+		LockTTL      time.Duration `mapstructure:"lockTTL"`
+
+		DueReminders       string `mapstructure:"dueReminders"`
+		OverdueNotices     string `mapstructure:"overdueNotices"`
+		ExpireStaleRecords string `mapstructure:"expireStaleRecords"`
+		NotificationEmails string `mapstructure:"notificationEmails"`
+		WebhookRetries     string `mapstructure:"webhookRetries"`
+
+		// ReminderLead is how long before the due date reminders go out.
+		ReminderLead time.Duration `mapstructure:"reminderLead"`
+	}
+
+	// Webhooks signing secrets are stored encrypted with SecretKey, which
+	// falls back to the JWT secret. Failed deliveries are retried after
+	// RetryBaseDelay, doubling each time up to MaxRetryDelay, until
+	// MaxAttempts attempts have been made.
+	Webhooks struct {
+		SecretKey      string        `mapstructure:"secretKey"`
+		Timeout        time.Duration `mapstructure:"timeout"`
+		MaxAttempts    int           `mapstructure:"maxAttempts"`
+		RetryBaseDelay time.Duration `mapstructure:"retryBaseDelay"`
+		MaxRetryDelay  time.Duration `mapstructure:"maxRetryDelay"`
+	}
 )
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAccountData downloads the caller's data as a JSON file, or with
+// ?format=zip as an archive holding one JSON file per record type.
+func (h *Handler) ExportAccountData(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid export format",
+			Error:   "format must be json or zip",
+		})
+		return
+	}
+
+	export, err := h.authService.ExportAccountData(c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to export account data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var data []byte
+	contentType := "application/json"
+	if format == "zip" {
+		data, err = exportArchive(export)
+		contentType = "application/zip"
+	} else {
+		data, err = json.MarshalIndent(export, "", "  ")
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to export account data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("elibrary-export-%d-%s.%s", export.Profile.ID, export.ExportedAt.Format("20060102"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func exportArchive(export *model.AccountExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"sessions.json", export.Sessions},
+		{"api_keys.json", export.APIKeys},
+		{"identities.json", export.Identities},
+		{"lockouts.json", export.Lockouts},
+		{"auth_events.json", export.AuthEvents},
+		{"loans.json", export.Loans},
+		{"holds.json", export.Holds},
+		{"fines.json", export.Fines},
+		{"notifications.json", export.Notifications},
+		{"notification_preferences.json", export.NotificationPreferences},
+		{"followed_authors.json", export.FollowedAuthors},
+		{"shelves.json", export.Shelves},
+		{"shelf_books.json", export.ShelfBooks},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	var req model.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := h.authService.DeleteAccount(c.GetInt("user_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			statusCode = http.StatusNotFound
+		case "current password is incorrect", "invalid two-factor code":
+			statusCode = http.StatusBadRequest
+		case "account is already deleted", "cannot delete the last admin", "account has books on loan", "account has outstanding fines":
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account deleted successfully",
+	})
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.authService.GetAPIKeys(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get API keys",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req model.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	key, err := h.authService.CreateAPIKey(c.GetInt("user_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "expiry must be in the future" {
+			statusCode = http.StatusBadRequest
+		} else if strings.HasSuffix(err.Error(), "is not allowed for your role") {
+			statusCode = http.StatusForbidden
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to create API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "API key created. Store the key now, it will not be shown again",
+		Data:    key,
+	})
+}
+
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid API key ID",
+			Error:   "API key ID must be a number",
+		})
+		return
+	}
+
+	err = h.authService.RevokeAPIKey(c.GetInt("user_id"), id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "API key not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to revoke API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
@@ -1,8 +1,13 @@
 package auth
 
 import (
+	"errors"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
 	authService "github.com/ferdy-adr/elibrary-backend/internal/service/auth"
 	"github.com/gin-gonic/gin"
@@ -19,10 +24,55 @@ func NewHandler(authService *authService.Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/.well-known/jwks.json", h.JWKS)
+
 	auth := r.Group("/api/auth")
 	{
 		auth.POST("/register", h.Register)
 		auth.POST("/login", h.Login)
+		auth.POST("/login/2fa", h.LoginTwoFactor)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/forgot-password", h.ForgotPassword)
+		auth.POST("/reset-password", h.ResetPassword)
+		auth.GET("/verify-email", h.VerifyEmail)
+		auth.POST("/verify-email", h.VerifyEmail)
+		auth.GET("/oidc/providers", h.GetOIDCProviders)
+		auth.GET("/oidc/:provider/login", h.StartOIDCLogin)
+		auth.GET("/oidc/:provider/callback", h.OIDCCallback)
+		auth.POST("/oidc/:provider/callback", h.OIDCCallback)
+	}
+
+	protected := r.Group("/api/auth")
+	protected.Use(middleware.JWTMiddleware(h.authService))
+	{
+		protected.POST("/logout", h.Logout)
+		protected.POST("/resend-verification", h.ResendVerification)
+		protected.GET("/me", h.GetProfile)
+		protected.PATCH("/me", h.UpdateProfile)
+		protected.DELETE("/me", h.DeleteAccount)
+		protected.GET("/me/export", h.ExportAccountData)
+		protected.POST("/change-password", h.ChangePassword)
+		protected.POST("/2fa/enroll", h.EnrollTwoFactor)
+		protected.GET("/2fa/qr", h.TwoFactorQRCode)
+		protected.POST("/2fa/verify", h.ConfirmTwoFactor)
+		protected.POST("/2fa/disable", h.DisableTwoFactor)
+		protected.POST("/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+		protected.GET("/api-keys", h.GetAPIKeys)
+		protected.POST("/api-keys", h.CreateAPIKey)
+		protected.DELETE("/api-keys/:id", h.RevokeAPIKey)
+		protected.GET("/identities", h.GetIdentities)
+		protected.GET("/sessions", h.GetSessions)
+		protected.DELETE("/sessions", h.RevokeAllSessions)
+		protected.DELETE("/sessions/:id", h.RevokeSession)
+	}
+
+	admin := r.Group("/api/admin/security")
+	admin.Use(middleware.JWTMiddleware(h.authService))
+	admin.Use(middleware.RequireRole(model.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor())
+	{
+		admin.GET("/2fa-policy", h.GetTwoFactorPolicy)
+		admin.PUT("/2fa-policy", h.UpdateTwoFactorPolicy)
 	}
 }
 
@@ -37,17 +87,14 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.Register(req)
+	user, err := h.authService.Register(req, clientInfo(c))
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "username already exists" {
-			statusCode = http.StatusConflict
-		}
-
+		statusCode, fields := validationErrorStatus(err, http.StatusInternalServerError)
 		c.JSON(statusCode, model.APIResponse{
 			Success: false,
 			Message: "Registration failed",
 			Error:   err.Error(),
+			Fields:  fields,
 		})
 		return
 	}
@@ -70,9 +117,9 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	loginResponse, err := h.authService.Login(req)
+	loginResponse, err := h.authService.Login(req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, model.APIResponse{
+		c.JSON(loginErrorStatus(c, err), model.APIResponse{
 			Success: false,
 			Message: "Login failed",
 			Error:   err.Error(),
@@ -80,9 +127,245 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	message := "Login successful"
+	if loginResponse.TwoFactorRequired {
+		message = "Two-factor authentication required"
+	}
+
 	c.JSON(http.StatusOK, model.APIResponse{
 		Success: true,
-		Message: "Login successful",
+		Message: message,
 		Data:    loginResponse,
 	})
 }
+
+// clientInfo describes the device making the request, for session records.
+func clientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// loginErrorStatus maps a login failure to its status code and sets
+// Retry-After when the attempt was throttled.
+func loginErrorStatus(c *gin.Context, err error) int {
+	if err.Error() == "account is deactivated" || err.Error() == "password reset required, check your email for a reset link" {
+		return http.StatusForbidden
+	}
+
+	var throttleErr *authService.LoginThrottleError
+	if !errors.As(err, &throttleErr) {
+		return http.StatusUnauthorized
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttleErr.RetryAfter.Seconds()))))
+	if throttleErr.Locked {
+		return http.StatusLocked
+	}
+	return http.StatusTooManyRequests
+}
+
+// validationErrorStatus returns 400, or 409 for conflicts, with the rejected
+// fields when err is a validation error, and statusCode otherwise.
+func validationErrorStatus(err error, statusCode int) (int, []model.FieldError) {
+	var validationErr *authService.ValidationError
+	if !errors.As(err, &validationErr) {
+		return statusCode, nil
+	}
+
+	if validationErr.Conflict {
+		return http.StatusConflict, validationErr.Fields
+	}
+	return http.StatusBadRequest, validationErr.Fields
+}
+
+// JWKS is served as a bare JSON Web Key Set rather than an APIResponse so
+// standard JWT libraries can consume it directly.
+func (h *Handler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+func (h *Handler) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(req, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Token refresh failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Token refreshed successfully",
+		Data:    tokens,
+	})
+}
+
+func (h *Handler) Logout(c *gin.Context) {
+	// The refresh token is optional; the current session is revoked either way
+	var req model.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+	sessionID := c.GetInt("session_id")
+	tokenID := c.GetString("token_id")
+	expiresAt := c.GetTime("token_expires_at")
+
+	err := h.authService.Logout(userID, sessionID, tokenID, expiresAt, req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid refresh token" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Logout failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Logout successful",
+	})
+}
+
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ForgotPassword(req, clientInfo(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to process password reset request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "If the email is registered, a password reset link has been sent",
+	})
+}
+
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := h.authService.ResetPassword(req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid or expired reset token" {
+			statusCode = http.StatusBadRequest
+		}
+		statusCode, fields := validationErrorStatus(err, statusCode)
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Password reset failed",
+			Error:   err.Error(),
+			Fields:  fields,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Password has been reset successfully",
+	})
+}
+
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	// GET carries the token in the query string (email link), POST in the body
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := h.authService.VerifyEmail(req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid or expired verification token" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Email verification failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Email verified successfully",
+	})
+}
+
+func (h *Handler) ResendVerification(c *gin.Context) {
+	err := h.authService.ResendVerificationEmail(c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "email already verified" {
+			statusCode = http.StatusConflict
+		} else if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to resend verification email",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Verification email sent",
+	})
+}
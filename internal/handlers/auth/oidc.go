@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Identity providers retrieved successfully",
+		Data:    h.authService.GetOIDCProviders(),
+	})
+}
+
+func (h *Handler) StartOIDCLogin(c *gin.Context) {
+	authorization, err := h.authService.StartOIDCLogin(c.Param("provider"))
+	if err != nil {
+		statusCode := http.StatusBadGateway
+		if err.Error() == "unknown identity provider" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to start login",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Redirect the user to the authorization URL",
+		Data:    authorization,
+	})
+}
+
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	// GET is the provider redirect, POST lets a frontend forward code and state
+	var req model.OIDCCallbackRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	loginResponse, err := h.authService.CompleteOIDCLogin(c.Param("provider"), req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "unknown identity provider":
+			statusCode = http.StatusNotFound
+		case "invalid or expired login state", "identity provider did not return an email address":
+			statusCode = http.StatusBadRequest
+		case "identity provider rejected the login":
+			statusCode = http.StatusUnauthorized
+		case "an account with this email already exists":
+			statusCode = http.StatusConflict
+		case "account is deactivated":
+			statusCode = http.StatusForbidden
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Login failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "Login successful"
+	if loginResponse.TwoFactorRequired {
+		message = "Two-factor authentication required"
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    loginResponse,
+	})
+}
+
+func (h *Handler) GetIdentities(c *gin.Context) {
+	identities, err := h.authService.GetIdentities(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get linked identities",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Linked identities retrieved successfully",
+		Data:    identities,
+	})
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetProfile(c *gin.Context) {
+	user, err := h.authService.GetProfile(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "User not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Profile retrieved successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) UpdateProfile(c *gin.Context) {
+	var req model.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(c.GetInt("user_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+		statusCode, fields := validationErrorStatus(err, statusCode)
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to update profile",
+			Error:   err.Error(),
+			Fields:  fields,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Profile updated successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) ChangePassword(c *gin.Context) {
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.authService.ChangePassword(c.GetInt("user_id"), c.GetInt("session_id"), req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "current password is incorrect" {
+			statusCode = http.StatusBadRequest
+		}
+		statusCode, fields := validationErrorStatus(err, statusCode)
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to change password",
+			Error:   err.Error(),
+			Fields:  fields,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Password changed successfully",
+		Data:    tokens,
+	})
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetSessions(c *gin.Context) {
+	sessions, err := h.authService.GetSessions(c.GetInt("user_id"), c.GetInt("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get sessions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Sessions retrieved successfully",
+		Data:    sessions,
+	})
+}
+
+func (h *Handler) RevokeSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid session ID",
+			Error:   "Session ID must be a number",
+		})
+		return
+	}
+
+	err = h.authService.RevokeSession(c.GetInt("user_id"), id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "session not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to revoke session",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Session revoked successfully",
+	})
+}
+
+// RevokeAllSessions logs out every device. ?keep_current=true keeps the
+// session making the request.
+func (h *Handler) RevokeAllSessions(c *gin.Context) {
+	keepSessionID := 0
+	if c.Query("keep_current") == "true" {
+		keepSessionID = c.GetInt("session_id")
+	}
+
+	err := h.authService.RevokeAllSessions(c.GetInt("user_id"), keepSessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to revoke sessions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Sessions revoked successfully",
+	})
+}
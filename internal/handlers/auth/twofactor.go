@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) LoginTwoFactor(c *gin.Context) {
+	var req model.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	loginResponse, err := h.authService.CompleteTwoFactorLogin(req, clientInfo(c))
+	if err != nil {
+		c.JSON(loginErrorStatus(c, err), model.APIResponse{
+			Success: false,
+			Message: "Login failed",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Login successful",
+		Data:    loginResponse,
+	})
+}
+
+func (h *Handler) EnrollTwoFactor(c *gin.Context) {
+	enrollment, err := h.authService.EnrollTwoFactor(c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "two-factor authentication is already enabled" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to start two-factor enrollment",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Scan the QR code with your authenticator app, then verify a code to finish",
+		Data:    enrollment,
+	})
+}
+
+func (h *Handler) TwoFactorQRCode(c *gin.Context) {
+	png, err := h.authService.TwoFactorQRCode(c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" || err.Error() == "no pending two-factor enrollment" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to render QR code",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (h *Handler) ConfirmTwoFactor(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	activation, err := h.authService.ConfirmTwoFactor(c.GetInt("user_id"), c.GetInt("session_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found", "no pending two-factor enrollment":
+			statusCode = http.StatusNotFound
+		case "two-factor authentication is already enabled":
+			statusCode = http.StatusConflict
+		case "invalid two-factor code":
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to enable two-factor authentication",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Two-factor authentication enabled. Store the recovery codes somewhere safe",
+		Data:    activation,
+	})
+}
+
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	var req model.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := h.authService.DisableTwoFactor(c.GetInt("user_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			statusCode = http.StatusNotFound
+		case "two-factor authentication is not enabled":
+			statusCode = http.StatusConflict
+		case "two-factor authentication is required for your role":
+			statusCode = http.StatusForbidden
+		case "current password is incorrect", "invalid two-factor code":
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to disable two-factor authentication",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(c.GetInt("user_id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			statusCode = http.StatusNotFound
+		case "two-factor authentication is not enabled":
+			statusCode = http.StatusConflict
+		case "invalid two-factor code":
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to regenerate recovery codes",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Recovery codes regenerated",
+		Data:    codes,
+	})
+}
+
+func (h *Handler) GetTwoFactorPolicy(c *gin.Context) {
+	policy, err := h.authService.GetTwoFactorPolicy()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get two-factor policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Two-factor policy retrieved successfully",
+		Data:    policy,
+	})
+}
+
+func (h *Handler) UpdateTwoFactorPolicy(c *gin.Context) {
+	var req model.TwoFactorPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.authService.UpdateTwoFactorPolicy(c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to update two-factor policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Two-factor policy updated successfully",
+		Data:    policy,
+	})
+}
@@ -0,0 +1,201 @@
+package books
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// copyIDs reads the book and copy IDs from the path, writing the error
+// response when either is not a number.
+func copyIDs(c *gin.Context) (int, int, bool) {
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid book ID",
+			Error:   "Book ID must be a number",
+		})
+		return 0, 0, false
+	}
+
+	copyID, err := strconv.Atoi(c.Param("copyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid copy ID",
+			Error:   "Copy ID must be a number",
+		})
+		return 0, 0, false
+	}
+
+	return bookID, copyID, true
+}
+
+func copyErrorStatus(err error) int {
+	switch err.Error() {
+	case "book not found", "copy not found":
+		return http.StatusNotFound
+	case "barcode already exists", "copy is on loan", "copy is on hold", "copy was changed, please try again":
+		return http.StatusConflict
+	case "no fields to update", "acquired_at must be a date in YYYY-MM-DD format":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetCopies(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid book ID",
+			Error:   "Book ID must be a number",
+		})
+		return
+	}
+
+	var params model.BookCopyQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	copies, err := h.bookService.GetCopies(bookID, params)
+	if err != nil {
+		c.JSON(copyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get copies",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copies retrieved successfully",
+		Data:    copies,
+	})
+}
+
+func (h *Handler) GetCopy(c *gin.Context) {
+	bookID, copyID, ok := copyIDs(c)
+	if !ok {
+		return
+	}
+
+	bookCopy, err := h.bookService.GetCopy(bookID, copyID)
+	if err != nil {
+		c.JSON(copyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copy retrieved successfully",
+		Data:    bookCopy,
+	})
+}
+
+func (h *Handler) CreateCopy(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid book ID",
+			Error:   "Book ID must be a number",
+		})
+		return
+	}
+
+	var req model.CreateBookCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	bookCopy, err := h.bookService.CreateCopy(bookID, req)
+	if err != nil {
+		c.JSON(copyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to create copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Copy created successfully",
+		Data:    bookCopy,
+	})
+}
+
+func (h *Handler) UpdateCopy(c *gin.Context) {
+	bookID, copyID, ok := copyIDs(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateBookCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	bookCopy, err := h.bookService.UpdateCopy(bookID, copyID, req)
+	if err != nil {
+		c.JSON(copyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copy updated successfully",
+		Data:    bookCopy,
+	})
+}
+
+func (h *Handler) DeleteCopy(c *gin.Context) {
+	bookID, copyID, ok := copyIDs(c)
+	if !ok {
+		return
+	}
+
+	err := h.bookService.DeleteCopy(bookID, copyID)
+	if err != nil {
+		c.JSON(copyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to delete copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copy deleted successfully",
+	})
+}
@@ -7,16 +7,25 @@ import (
 	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
 	bookService "github.com/ferdy-adr/elibrary-backend/internal/service/books"
+	shelfService "github.com/ferdy-adr/elibrary-backend/internal/service/shelves"
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	bookService *bookService.Service
+	bookService   *bookService.Service
+	shelfService  *shelfService.Service
+	authenticator middleware.Authenticator
 }
 
-func NewHandler(bookService *bookService.Service) *Handler {
+func NewHandler(
+	bookService *bookService.Service,
+	shelfService *shelfService.Service,
+	authenticator middleware.Authenticator,
+) *Handler {
 	return &Handler{
-		bookService: bookService,
+		bookService:   bookService,
+		shelfService:  shelfService,
+		authenticator: authenticator,
 	}
 }
 
@@ -25,16 +34,24 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	public := r.Group("/api/books")
 	{
 		public.GET("", h.GetBooks)
-		public.GET("/:id", h.GetBookByID)
+		public.GET("/:id", middleware.OptionalJWTMiddleware(h.authenticator), h.GetBookByID)
+		public.GET("/:id/copies", h.GetCopies)
+		public.GET("/:id/copies/:copyId", h.GetCopy)
 	}
 
-	// Protected routes (for managing books)
+	// Protected routes (for managing books, librarians and admins only)
 	protected := r.Group("/api/books")
-	protected.Use(middleware.JWTMiddleware())
+	protected.Use(middleware.AuthMiddleware(h.authenticator, model.ScopeBooksWrite))
+	protected.Use(middleware.RequireRole(model.RoleLibrarian, model.RoleAdmin))
+	protected.Use(middleware.RequireVerifiedEmail())
+	protected.Use(middleware.RequireTwoFactor())
 	{
 		protected.POST("", h.CreateBook)
 		protected.PATCH("/:id", h.UpdateBook)
 		protected.DELETE("/:id", h.DeleteBook)
+		protected.POST("/:id/copies", h.CreateCopy)
+		protected.PATCH("/:id/copies/:copyId", h.UpdateCopy)
+		protected.DELETE("/:id/copies/:copyId", h.DeleteCopy)
 	}
 
 	// Static files for images
@@ -90,10 +107,34 @@ func (h *Handler) GetBookByID(c *gin.Context) {
 		return
 	}
 
+	// Signed-in callers also see which of their shelves hold the book
+	userID := c.GetInt("user_id")
+	if userID == 0 {
+		c.JSON(http.StatusOK, model.APIResponse{
+			Success: true,
+			Message: "Book retrieved successfully",
+			Data:    book,
+		})
+		return
+	}
+
+	shelves, err := h.shelfService.GetBookShelves(userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get book shelves",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Book retrieved successfully",
-		Data:    book,
+		Data: model.BookWithShelves{
+			Book:    *book,
+			Shelves: shelves,
+		},
 	})
 }
 
@@ -197,6 +238,8 @@ func (h *Handler) DeleteBook(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "book not found" {
 			statusCode = http.StatusNotFound
+		} else if err.Error() == "book has copies on loan" {
+			statusCode = http.StatusConflict
 		}
 
 		c.JSON(statusCode, model.APIResponse{
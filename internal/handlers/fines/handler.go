@@ -0,0 +1,208 @@
+package fines
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	fineService "github.com/ferdy-adr/elibrary-backend/internal/service/fines"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	fineService   *fineService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(fineService *fineService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		fineService:   fineService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Member routes (own ledger)
+	member := r.Group("/api/fines")
+	member.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		member.GET("/me", h.GetMyLedger)
+	}
+
+	// Circulation desk routes (librarians and admins only)
+	staff := r.Group("/api/fines")
+	staff.Use(middleware.AuthMiddleware(h.authenticator, model.ScopeLoansWrite))
+	staff.Use(middleware.RequireRole(model.RoleLibrarian, model.RoleAdmin))
+	staff.Use(middleware.RequireVerifiedEmail())
+	staff.Use(middleware.RequireTwoFactor())
+	{
+		staff.GET("", h.GetFines)
+		staff.GET("/users/:userId", h.GetUserLedger)
+		staff.POST("/:id/settle", h.SettleFine)
+		staff.POST("/:id/waive", h.WaiveFine)
+	}
+}
+
+func fineErrorStatus(err error) int {
+	switch err.Error() {
+	case "fine not found":
+		return http.StatusNotFound
+	case "fine is already resolved":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetMyLedger(c *gin.Context) {
+	ledger, err := h.fineService.GetLedger(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get fines",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fines retrieved successfully",
+		Data:    ledger,
+	})
+}
+
+func (h *Handler) GetUserLedger(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	ledger, err := h.fineService.GetLedger(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get fines",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fines retrieved successfully",
+		Data:    ledger,
+	})
+}
+
+func (h *Handler) GetFines(c *gin.Context) {
+	var params model.FineQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.fineService.GetFines(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get fines",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fines retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) SettleFine(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid fine ID",
+			Error:   "Fine ID must be a number",
+		})
+		return
+	}
+
+	// The note is optional, so an empty body is accepted
+	var req model.SettleFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	fine, err := h.fineService.SettleFine(id, c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(fineErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to settle fine",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fine settled successfully",
+		Data:    fine,
+	})
+}
+
+func (h *Handler) WaiveFine(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid fine ID",
+			Error:   "Fine ID must be a number",
+		})
+		return
+	}
+
+	var req model.WaiveFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	fine, err := h.fineService.WaiveFine(id, c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(fineErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to waive fine",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fine waived successfully",
+		Data:    fine,
+	})
+}
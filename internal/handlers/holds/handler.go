@@ -0,0 +1,172 @@
+package holds
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	holdService "github.com/ferdy-adr/elibrary-backend/internal/service/holds"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	holdService   *holdService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(holdService *holdService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		holdService:   holdService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Member routes (own holds)
+	member := r.Group("/api/holds")
+	member.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		member.POST("", middleware.RequireVerifiedEmail(), h.PlaceHold)
+		member.GET("/me", h.GetMyHolds)
+		member.POST("/:id/cancel", h.CancelHold)
+	}
+
+	// Circulation desk routes (librarians and admins only)
+	staff := r.Group("/api/holds")
+	staff.Use(middleware.AuthMiddleware(h.authenticator, model.ScopeLoansWrite))
+	staff.Use(middleware.RequireRole(model.RoleLibrarian, model.RoleAdmin))
+	staff.Use(middleware.RequireVerifiedEmail())
+	staff.Use(middleware.RequireTwoFactor())
+	{
+		staff.GET("", h.GetHolds)
+	}
+}
+
+func holdErrorStatus(err error) int {
+	switch err.Error() {
+	case "book not found", "hold not found":
+		return http.StatusNotFound
+	case "book has no copies", "book has available copies", "hold already placed",
+		"book is already on loan to you", "hold is already closed":
+		return http.StatusConflict
+	case "hold limit reached for membership tier":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) PlaceHold(c *gin.Context) {
+	var req model.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	hold, err := h.holdService.PlaceHold(c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to place hold",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Hold placed successfully",
+		Data:    hold,
+	})
+}
+
+func (h *Handler) GetMyHolds(c *gin.Context) {
+	var params model.HoldQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.holdService.GetUserHolds(c.GetInt("user_id"), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get holds",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Holds retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) CancelHold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid hold ID",
+			Error:   "Hold ID must be a number",
+		})
+		return
+	}
+
+	role := c.GetString("role")
+	staff := role == model.RoleLibrarian || role == model.RoleAdmin
+
+	hold, err := h.holdService.CancelHold(id, c.GetInt("user_id"), staff)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to cancel hold",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Hold cancelled successfully",
+		Data:    hold,
+	})
+}
+
+func (h *Handler) GetHolds(c *gin.Context) {
+	var params model.HoldQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.holdService.GetHolds(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get holds",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Holds retrieved successfully",
+		Data:    response,
+	})
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	jobService "github.com/ferdy-adr/elibrary-backend/internal/service/jobs"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	jobService    *jobService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(jobService *jobService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		jobService:    jobService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Admin routes (for monitoring background jobs)
+	admin := r.Group("/api/admin/jobs")
+	admin.Use(middleware.JWTMiddleware(h.authenticator))
+	admin.Use(middleware.RequireRole(model.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor())
+	{
+		admin.GET("", h.GetJobs)
+		admin.POST("/:name/run", h.RunJob)
+	}
+}
+
+func (h *Handler) GetJobs(c *gin.Context) {
+	jobs, err := h.jobService.GetJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get jobs",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Jobs retrieved successfully",
+		Data:    jobs,
+	})
+}
+
+// RunJob queues a job to run on the next scheduler poll.
+func (h *Handler) RunJob(c *gin.Context) {
+	job, err := h.jobService.RunNow(c.Param("name"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "job not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to run job",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Job scheduled to run",
+		Data:    job,
+	})
+}
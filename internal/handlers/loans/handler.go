@@ -0,0 +1,229 @@
+package loans
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	loanService "github.com/ferdy-adr/elibrary-backend/internal/service/loans"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	loanService   *loanService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(loanService *loanService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		loanService:   loanService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Member routes (own loan history)
+	member := r.Group("/api/loans")
+	member.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		member.GET("/me", h.GetMyLoans)
+		member.POST("/me/:id/renew", middleware.RequireVerifiedEmail(), h.RenewMyLoan)
+	}
+
+	// Circulation desk routes (librarians and admins only)
+	staff := r.Group("/api/loans")
+	staff.Use(middleware.AuthMiddleware(h.authenticator, model.ScopeLoansWrite))
+	staff.Use(middleware.RequireRole(model.RoleLibrarian, model.RoleAdmin))
+	staff.Use(middleware.RequireVerifiedEmail())
+	staff.Use(middleware.RequireTwoFactor())
+	{
+		staff.GET("", h.GetLoans)
+		staff.GET("/:id", h.GetLoan)
+		staff.POST("", h.Checkout)
+		staff.POST("/return", h.ReturnCopy)
+		staff.POST("/:id/return", h.ReturnLoan)
+		staff.POST("/:id/renew", h.RenewLoan)
+	}
+}
+
+func loanErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "copy not found", "loan not found":
+		return http.StatusNotFound
+	case "copy is not available", "copy is on hold for another member", "copy is not on loan", "loan is already returned",
+		"loan was changed, please try again":
+		return http.StatusConflict
+	case "user account is deactivated", "email address is not verified", "fine balance exceeds the borrowing limit", "loan limit reached for membership tier":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetLoans(c *gin.Context) {
+	var params model.LoanQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.loanService.GetLoans(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get loans",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Loans retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) GetMyLoans(c *gin.Context) {
+	var params model.LoanQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.loanService.GetUserLoans(c.GetInt("user_id"), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get loans",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Loans retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) GetLoan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid loan ID",
+			Error:   "Loan ID must be a number",
+		})
+		return
+	}
+
+	loan, err := h.loanService.GetLoan(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Loan not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Loan retrieved successfully",
+		Data:    loan,
+	})
+}
+
+func (h *Handler) Checkout(c *gin.Context) {
+	var req model.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	loan, err := h.loanService.Checkout(req, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(loanErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to check out copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Copy checked out successfully",
+		Data:    loan,
+	})
+}
+
+func (h *Handler) ReturnCopy(c *gin.Context) {
+	var req model.ReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.loanService.ReturnCopy(req.Barcode, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(loanErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to return copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copy returned successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) ReturnLoan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid loan ID",
+			Error:   "Loan ID must be a number",
+		})
+		return
+	}
+
+	response, err := h.loanService.ReturnLoan(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(loanErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to return copy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Copy returned successfully",
+		Data:    response,
+	})
+}
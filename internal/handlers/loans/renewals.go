@@ -0,0 +1,59 @@
+package loans
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	loanService "github.com/ferdy-adr/elibrary-backend/internal/service/loans"
+	"github.com/gin-gonic/gin"
+)
+
+// RenewMyLoan lets members renew their own loans without visiting the desk.
+func (h *Handler) RenewMyLoan(c *gin.Context) {
+	h.renewLoan(c, false)
+}
+
+func (h *Handler) RenewLoan(c *gin.Context) {
+	h.renewLoan(c, true)
+}
+
+func (h *Handler) renewLoan(c *gin.Context, staff bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid loan ID",
+			Error:   "Loan ID must be a number",
+		})
+		return
+	}
+
+	loan, err := h.loanService.RenewLoan(id, c.GetInt("user_id"), staff)
+	if err != nil {
+		var refusedErr *loanService.RenewalRefusedError
+		if errors.As(err, &refusedErr) {
+			c.JSON(http.StatusConflict, model.APIResponse{
+				Success: false,
+				Message: "Renewal refused",
+				Data:    refusedErr.Reasons,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(loanErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to renew loan",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Loan renewed successfully",
+		Data:    loan,
+	})
+}
@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetFollows(c *gin.Context) {
+	follows, err := h.notificationService.GetFollows(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get followed authors",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Followed authors retrieved successfully",
+		Data:    follows,
+	})
+}
+
+func (h *Handler) FollowAuthor(c *gin.Context) {
+	var req model.FollowAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	follow, err := h.notificationService.FollowAuthor(c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to follow author",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Author followed successfully",
+		Data:    follow,
+	})
+}
+
+func (h *Handler) UnfollowAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid follow ID",
+			Error:   "Follow ID must be a number",
+		})
+		return
+	}
+
+	if err := h.notificationService.UnfollowAuthor(id, c.GetInt("user_id")); err != nil {
+		c.JSON(notificationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to unfollow author",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Author unfollowed successfully",
+	})
+}
@@ -0,0 +1,175 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	notificationService "github.com/ferdy-adr/elibrary-backend/internal/service/notifications"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	notificationService *notificationService.Service
+	authenticator       middleware.Authenticator
+}
+
+func NewHandler(notificationService *notificationService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		notificationService: notificationService,
+		authenticator:       authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Member routes (own inbox and preferences)
+	notifications := r.Group("/api/notifications")
+	notifications.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		notifications.GET("", h.GetNotifications)
+		notifications.POST("/read-all", h.MarkAllRead)
+		notifications.POST("/:id/read", h.MarkRead)
+		notifications.GET("/preferences", h.GetPreferences)
+		notifications.PATCH("/preferences/:type", h.UpdatePreference)
+	}
+
+	// Member routes (own followed authors)
+	follows := r.Group("/api/followed-authors")
+	follows.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		follows.GET("", h.GetFollows)
+		follows.POST("", h.FollowAuthor)
+		follows.DELETE("/:id", h.UnfollowAuthor)
+	}
+}
+
+func notificationErrorStatus(err error) int {
+	switch err.Error() {
+	case "notification not found", "unknown notification type", "follow not found":
+		return http.StatusNotFound
+	case "author already followed":
+		return http.StatusConflict
+	case "no fields to update", "author is required":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetNotifications(c *gin.Context) {
+	var params model.NotificationQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.notificationService.GetNotifications(c.GetInt("user_id"), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get notifications",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Notifications retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid notification ID",
+			Error:   "Notification ID must be a number",
+		})
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to mark notification as read",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Notification marked as read",
+		Data:    notification,
+	})
+}
+
+func (h *Handler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationService.MarkAllRead(c.GetInt("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to mark notifications as read",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "All notifications marked as read",
+	})
+}
+
+func (h *Handler) GetPreferences(c *gin.Context) {
+	preferences, err := h.notificationService.GetPreferences(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get notification preferences",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Notification preferences retrieved successfully",
+		Data:    preferences,
+	})
+}
+
+func (h *Handler) UpdatePreference(c *gin.Context) {
+	var req model.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	preference, err := h.notificationService.UpdatePreference(c.GetInt("user_id"), c.Param("type"), req)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update notification preference",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Notification preference updated successfully",
+		Data:    preference,
+	})
+}
@@ -0,0 +1,380 @@
+package shelves
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	shelfService "github.com/ferdy-adr/elibrary-backend/internal/service/shelves"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	shelfService  *shelfService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(shelfService *shelfService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		shelfService:  shelfService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Public routes (public shelves, plus the caller's own when signed in)
+	public := r.Group("/api")
+	public.Use(middleware.OptionalJWTMiddleware(h.authenticator))
+	{
+		public.GET("/shelves/:id", h.GetShelf)
+		public.GET("/shelves/:id/books", h.GetShelfBooks)
+		public.GET("/users/:id/shelves", h.GetUserShelves)
+	}
+
+	// Member routes (own shelves)
+	member := r.Group("/api/shelves")
+	member.Use(middleware.JWTMiddleware(h.authenticator))
+	{
+		member.GET("", h.GetShelves)
+		member.POST("", h.CreateShelf)
+		member.PATCH("/:id", h.UpdateShelf)
+		member.DELETE("/:id", h.DeleteShelf)
+		member.POST("/:id/books", h.AddBook)
+		member.PUT("/:id/books/order", h.ReorderBooks)
+		member.DELETE("/:id/books/:bookId", h.RemoveBook)
+	}
+}
+
+func shelfErrorStatus(err error) int {
+	switch err.Error() {
+	case "shelf not found", "book not found", "book is not on this shelf":
+		return http.StatusNotFound
+	case "shelf name already exists", "shelf name is reserved", "book is already on this shelf",
+		"reading status shelves cannot be renamed", "reading status shelves cannot be deleted":
+		return http.StatusConflict
+	case "no fields to update", "shelf name is required",
+		"book_ids must list every book on the shelf exactly once":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetShelves(c *gin.Context) {
+	shelves, err := h.shelfService.GetShelves(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get shelves",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelves retrieved successfully",
+		Data:    shelves,
+	})
+}
+
+// GetUserShelves lists a user's public shelves; signed-in users looking at
+// their own also see private ones.
+func (h *Handler) GetUserShelves(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	shelves, err := h.shelfService.GetUserShelves(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get shelves",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelves retrieved successfully",
+		Data:    shelves,
+	})
+}
+
+func (h *Handler) GetShelf(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	shelf, err := h.shelfService.GetShelf(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelf retrieved successfully",
+		Data:    shelf,
+	})
+}
+
+func (h *Handler) GetShelfBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	var params model.ShelfBookQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.shelfService.GetShelfBooks(id, c.GetInt("user_id"), params)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get shelf books",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelf books retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) CreateShelf(c *gin.Context) {
+	var req model.CreateShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	shelf, err := h.shelfService.CreateShelf(c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to create shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Shelf created successfully",
+		Data:    shelf,
+	})
+}
+
+func (h *Handler) UpdateShelf(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	var req model.UpdateShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	shelf, err := h.shelfService.UpdateShelf(id, c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelf updated successfully",
+		Data:    shelf,
+	})
+}
+
+func (h *Handler) DeleteShelf(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	err = h.shelfService.DeleteShelf(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to delete shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelf deleted successfully",
+	})
+}
+
+func (h *Handler) AddBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	var req model.AddShelfBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	shelf, err := h.shelfService.AddBook(id, c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to add book to shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Book added to shelf",
+		Data:    shelf,
+	})
+}
+
+func (h *Handler) ReorderBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	var req model.ReorderShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err = h.shelfService.ReorderBooks(id, c.GetInt("user_id"), req)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to reorder shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Shelf reordered successfully",
+	})
+}
+
+func (h *Handler) RemoveBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid shelf ID",
+			Error:   "Shelf ID must be a number",
+		})
+		return
+	}
+
+	bookID, err := strconv.Atoi(c.Param("bookId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid book ID",
+			Error:   "Book ID must be a number",
+		})
+		return
+	}
+
+	err = h.shelfService.RemoveBook(id, c.GetInt("user_id"), bookID)
+	if err != nil {
+		c.JSON(shelfErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to remove book from shelf",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Book removed from shelf",
+	})
+}
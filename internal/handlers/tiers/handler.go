@@ -0,0 +1,228 @@
+package tiers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	tierService "github.com/ferdy-adr/elibrary-backend/internal/service/tiers"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	tierService   *tierService.Service
+	authenticator middleware.Authenticator
+}
+
+func NewHandler(tierService *tierService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		tierService:   tierService,
+		authenticator: authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Admin routes (for managing membership tiers)
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.JWTMiddleware(h.authenticator))
+	admin.Use(middleware.RequireRole(model.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor())
+	{
+		admin.GET("/membership-tiers", h.GetTiers)
+		admin.GET("/membership-tiers/:id", h.GetTier)
+		admin.POST("/membership-tiers", h.CreateTier)
+		admin.PATCH("/membership-tiers/:id", h.UpdateTier)
+		admin.DELETE("/membership-tiers/:id", h.DeleteTier)
+		admin.PATCH("/users/:id/tier", h.UpdateUserTier)
+	}
+}
+
+func tierErrorStatus(err error) int {
+	switch err.Error() {
+	case "tier not found", "user not found":
+		return http.StatusNotFound
+	case "tier name already exists", "cannot delete the default tier", "tier is assigned to users":
+		return http.StatusConflict
+	case "no fields to update":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetTiers(c *gin.Context) {
+	tiers, err := h.tierService.GetTiers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get membership tiers",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Membership tiers retrieved successfully",
+		Data:    tiers,
+	})
+}
+
+func (h *Handler) GetTier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid tier ID",
+			Error:   "Tier ID must be a number",
+		})
+		return
+	}
+
+	tier, err := h.tierService.GetTier(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Membership tier not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Membership tier retrieved successfully",
+		Data:    tier,
+	})
+}
+
+func (h *Handler) CreateTier(c *gin.Context) {
+	var req model.CreateMembershipTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tier, err := h.tierService.CreateTier(req)
+	if err != nil {
+		c.JSON(tierErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to create membership tier",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Membership tier created successfully",
+		Data:    tier,
+	})
+}
+
+func (h *Handler) UpdateTier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid tier ID",
+			Error:   "Tier ID must be a number",
+		})
+		return
+	}
+
+	var req model.UpdateMembershipTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tier, err := h.tierService.UpdateTier(id, req)
+	if err != nil {
+		c.JSON(tierErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update membership tier",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Membership tier updated successfully",
+		Data:    tier,
+	})
+}
+
+func (h *Handler) DeleteTier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid tier ID",
+			Error:   "Tier ID must be a number",
+		})
+		return
+	}
+
+	err = h.tierService.DeleteTier(id)
+	if err != nil {
+		c.JSON(tierErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to delete membership tier",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Membership tier deleted successfully",
+	})
+}
+
+func (h *Handler) UpdateUserTier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	var req model.UpdateUserTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := h.tierService.AssignTier(id, req)
+	if err != nil {
+		c.JSON(tierErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update user tier",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User tier updated successfully",
+		Data:    user,
+	})
+}
@@ -0,0 +1,396 @@
+package users
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	authService "github.com/ferdy-adr/elibrary-backend/internal/service/auth"
+	userService "github.com/ferdy-adr/elibrary-backend/internal/service/users"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	userService *userService.Service
+	authService *authService.Service
+}
+
+func NewHandler(userService *userService.Service, authService *authService.Service) *Handler {
+	return &Handler{
+		userService: userService,
+		authService: authService,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Admin routes (for managing users)
+	admin := r.Group("/api/admin/users")
+	admin.Use(middleware.JWTMiddleware(h.authService))
+	admin.Use(middleware.RequireRole(model.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor())
+	{
+		admin.GET("", h.GetUsers)
+		admin.GET("/:id", h.GetUser)
+		admin.PATCH("/:id/role", h.UpdateRole)
+		admin.POST("/:id/deactivate", h.DeactivateUser)
+		admin.POST("/:id/reactivate", h.ReactivateUser)
+		admin.POST("/:id/force-password-reset", h.ForcePasswordReset)
+		admin.POST("/:id/unlock", h.UnlockUser)
+		admin.DELETE("/:id", h.DeleteUser)
+	}
+
+	lockouts := r.Group("/api/admin/lockouts")
+	lockouts.Use(middleware.JWTMiddleware(h.authService))
+	lockouts.Use(middleware.RequireRole(model.RoleAdmin))
+	lockouts.Use(middleware.RequireTwoFactor())
+	{
+		lockouts.GET("", h.GetLockouts)
+	}
+
+	authEvents := r.Group("/api/admin/auth-events")
+	authEvents.Use(middleware.JWTMiddleware(h.authService))
+	authEvents.Use(middleware.RequireRole(model.RoleAdmin))
+	authEvents.Use(middleware.RequireTwoFactor())
+	{
+		authEvents.GET("", h.GetAuthEvents)
+	}
+}
+
+func (h *Handler) GetUsers(c *gin.Context) {
+	var params model.UserQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.userService.GetUsers(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get users",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Users retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) GetUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	user, err := h.userService.GetUser(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to get user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User retrieved successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) UpdateRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := h.userService.UpdateRole(id, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot remove the last admin" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to update user role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User role updated successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) DeactivateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	user, err := h.userService.DeactivateUser(id, c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			statusCode = http.StatusNotFound
+		case "cannot deactivate your own account":
+			statusCode = http.StatusBadRequest
+		case "user is already deactivated", "cannot deactivate the last admin":
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to deactivate user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User deactivated successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) ReactivateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	user, err := h.userService.ReactivateUser(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "user is not deactivated" || err.Error() == "user has been deleted" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to reactivate user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User reactivated successfully",
+		Data:    user,
+	})
+}
+
+func (h *Handler) ForcePasswordReset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	err = h.authService.ForcePasswordReset(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to force password reset",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Password reset required, a reset link has been sent to the user",
+	})
+}
+
+func (h *Handler) UnlockUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	err = h.userService.UnlockUser(id, c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to unlock user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User unlocked successfully",
+	})
+}
+
+// DeleteUser anonymizes an account on the user's behalf, for deletion
+// requests that arrive outside the app.
+func (h *Handler) DeleteUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   "User ID must be a number",
+		})
+		return
+	}
+
+	err = h.authService.DeleteUserAccount(id, c.GetInt("user_id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found":
+			statusCode = http.StatusNotFound
+		case "cannot delete your own account here":
+			statusCode = http.StatusBadRequest
+		case "account is already deleted", "cannot delete the last admin", "account has books on loan", "account has outstanding fines":
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "User deleted successfully",
+	})
+}
+
+func (h *Handler) GetLockouts(c *gin.Context) {
+	var params model.LockoutQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.userService.GetLockouts(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get lockouts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Lockouts retrieved successfully",
+		Data:    response,
+	})
+}
+
+func (h *Handler) GetAuthEvents(c *gin.Context) {
+	var params model.AuthEventQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.userService.GetAuthEvents(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get auth events",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Auth events retrieved successfully",
+		Data:    response,
+	})
+}
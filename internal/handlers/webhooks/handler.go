@@ -0,0 +1,291 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/middleware"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	webhookService "github.com/ferdy-adr/elibrary-backend/internal/service/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	webhookService *webhookService.Service
+	authenticator  middleware.Authenticator
+}
+
+func NewHandler(webhookService *webhookService.Service, authenticator middleware.Authenticator) *Handler {
+	return &Handler{
+		webhookService: webhookService,
+		authenticator:  authenticator,
+	}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	// Admin routes (for managing webhook subscriptions)
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.JWTMiddleware(h.authenticator))
+	admin.Use(middleware.RequireRole(model.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor())
+	{
+		admin.GET("/webhooks", h.GetWebhooks)
+		admin.GET("/webhooks/:id", h.GetWebhook)
+		admin.POST("/webhooks", h.CreateWebhook)
+		admin.PATCH("/webhooks/:id", h.UpdateWebhook)
+		admin.DELETE("/webhooks/:id", h.DeleteWebhook)
+		admin.GET("/webhooks/:id/deliveries", h.GetDeliveries)
+		admin.POST("/webhooks/:id/deliveries/replay", h.ReplayFailedDeliveries)
+		admin.POST("/webhook-deliveries/:id/replay", h.ReplayDelivery)
+	}
+}
+
+func webhookErrorStatus(err error) int {
+	switch err.Error() {
+	case "webhook not found", "delivery not found":
+		return http.StatusNotFound
+	case "only failed deliveries can be replayed", "delivery was already replayed":
+		return http.StatusConflict
+	case "no fields to update", "webhook URL must be an http or https URL":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.GetWebhooks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get webhooks",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhooks retrieved successfully",
+		Data:    webhooks,
+	})
+}
+
+func (h *Handler) GetWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+			Error:   "Webhook ID must be a number",
+		})
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook retrieved successfully",
+		Data:    webhook,
+	})
+}
+
+// CreateWebhook registers a webhook. The signing secret is only returned
+// in this response.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var req model.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(req, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to create webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Webhook created successfully. Store the secret now; it will not be shown again",
+		Data:    webhook,
+	})
+}
+
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+			Error:   "Webhook ID must be a number",
+		})
+		return
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(id, req)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    webhook,
+	})
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+			Error:   "Webhook ID must be a number",
+		})
+		return
+	}
+
+	err = h.webhookService.DeleteWebhook(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to delete webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+func (h *Handler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+			Error:   "Webhook ID must be a number",
+		})
+		return
+	}
+
+	var params model.WebhookDeliveryQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.webhookService.GetDeliveries(id, params)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get webhook deliveries",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook deliveries retrieved successfully",
+		Data:    response,
+	})
+}
+
+// ReplayDelivery queues a failed delivery to be sent again.
+func (h *Handler) ReplayDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid delivery ID",
+			Error:   "Delivery ID must be a number",
+		})
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to replay webhook delivery",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Webhook delivery queued for replay",
+		Data:    delivery,
+	})
+}
+
+// ReplayFailedDeliveries queues every failed delivery of a webhook to be
+// sent again.
+func (h *Handler) ReplayFailedDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid webhook ID",
+			Error:   "Webhook ID must be a number",
+		})
+		return
+	}
+
+	deliveries, err := h.webhookService.ReplayFailedDeliveries(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to replay webhook deliveries",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Failed webhook deliveries queued for replay",
+		Data:    deliveries,
+	})
+}
@@ -4,14 +4,48 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/ferdy-adr/elibrary-backend/internal/configs"
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func JWTMiddleware() gin.HandlerFunc {
+// Authenticator resolves the caller of a request from an access token or an
+// API key. Rejected credentials are reported through RecordAuthEvent.
+type Authenticator interface {
+	ValidateAccessToken(tokenString string) (*model.Principal, error)
+	AuthenticateAPIKey(key string) (*model.Principal, error)
+	RecordAuthEvent(event *model.AuthEvent)
+}
+
+// JWTMiddleware only accepts bearer access tokens.
+func JWTMiddleware(authenticator Authenticator) gin.HandlerFunc {
+	return AuthMiddleware(authenticator)
+}
+
+// OptionalJWTMiddleware identifies the caller on public routes when a
+// valid bearer access token is sent. A missing, expired or revoked token
+// is treated as an anonymous request rather than rejected.
+func OptionalJWTMiddleware(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString != "" {
+			if principal, err := authenticator.ValidateAccessToken(tokenString); err == nil {
+				setPrincipal(c, principal)
+			}
+		}
+		c.Next()
+	}
+}
+
+// AuthMiddleware accepts a bearer access token or, when scopes are given, an
+// X-API-Key header holding all of those scopes. Routes that declare no scopes
+// are never reachable with an API key.
+func AuthMiddleware(authenticator Authenticator, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, authenticator, apiKey, scopes)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, model.APIResponse{
@@ -34,27 +68,84 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Parse token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(configs.Get().JWT.SecretKey), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Parse and verify token
+		principal, err := authenticator.ValidateAccessToken(tokenString)
+		if err != nil {
+			recordRejection(c, authenticator, model.AuthEventAccessTokenRejected, err)
 			c.JSON(http.StatusUnauthorized, model.APIResponse{
 				Success: false,
 				Message: "Invalid token",
-				Error:   "invalid_token",
+				Error:   err.Error(),
 			})
 			c.Abort()
 			return
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user_id", int(claims["user_id"].(float64)))
-			c.Set("username", claims["username"].(string))
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+func authenticateAPIKey(c *gin.Context, authenticator Authenticator, apiKey string, scopes []string) {
+	if len(scopes) == 0 {
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "API keys are not accepted for this endpoint",
+			Error:   "api_key_not_allowed",
+		})
+		c.Abort()
+		return
+	}
+
+	principal, err := authenticator.AuthenticateAPIKey(apiKey)
+	if err != nil {
+		recordRejection(c, authenticator, model.AuthEventAPIKeyRejected, err)
+		c.JSON(http.StatusUnauthorized, model.APIResponse{
+			Success: false,
+			Message: "Invalid API key",
+			Error:   err.Error(),
+		})
+		c.Abort()
+		return
+	}
+
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			c.JSON(http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "API key is missing the required scope " + scope,
+				Error:   "insufficient_scope",
+			})
+			c.Abort()
+			return
 		}
+	}
 
-		c.Next()
+	setPrincipal(c, principal)
+	c.Next()
+}
+
+func recordRejection(c *gin.Context, authenticator Authenticator, eventType string, err error) {
+	authenticator.RecordAuthEvent(&model.AuthEvent{
+		Type:      eventType,
+		Outcome:   model.AuthOutcomeFailure,
+		Reason:    err.Error(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+}
+
+func setPrincipal(c *gin.Context, principal *model.Principal) {
+	c.Set("principal", principal)
+	c.Set("user_id", principal.UserID)
+	c.Set("username", principal.Username)
+	c.Set("role", principal.Role)
+	c.Set("email_verified", principal.EmailVerified)
+	c.Set("two_factor_setup_required", principal.TwoFactorSetupRequired)
+	c.Set("token_id", principal.TokenID)
+	c.Set("token_expires_at", principal.TokenExpiresAt)
+	c.Set("session_id", principal.SessionID)
+	if principal.APIKeyID != 0 {
+		c.Set("api_key_id", principal.APIKeyID)
 	}
 }
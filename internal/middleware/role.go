@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets requests through when the authenticated user has one
+// of the given roles. It must run after JWTMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, model.APIResponse{
+			Success: false,
+			Message: "You do not have permission to perform this action",
+			Error:   "insufficient_role",
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireTwoFactor blocks privileged actions for users whose role requires
+// two-factor authentication but who have not enrolled yet. It must run after
+// JWTMiddleware.
+func RequireTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("two_factor_setup_required") {
+			c.JSON(http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "Two-factor authentication must be enabled for your role",
+				Error:   "two_factor_setup_required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail blocks sensitive actions for users who have not yet
+// confirmed their email address. It must run after JWTMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("email_verified") {
+			c.JSON(http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "Please verify your email address first",
+				Error:   "email_not_verified",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
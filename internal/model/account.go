@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// AccountExport bundles everything stored about a user for a personal data
+// export. Secrets such as password and token hashes are left out.
+type AccountExport struct {
+	ExportedAt              time.Time                `json:"exported_at"`
+	Profile                 *User                    `json:"profile"`
+	Sessions                []Session                `json:"sessions"`
+	APIKeys                 []APIKey                 `json:"api_keys"`
+	Identities              []UserIdentity           `json:"identities"`
+	Lockouts                []AccountLockout         `json:"lockouts"`
+	AuthEvents              []AuthEvent              `json:"auth_events"`
+	Loans                   []Loan                   `json:"loans"`
+	Holds                   []Hold                   `json:"holds"`
+	Fines                   []Fine                   `json:"fines"`
+	Notifications           []Notification           `json:"notifications"`
+	NotificationPreferences []NotificationPreference `json:"notification_preferences"`
+	FollowedAuthors         []AuthorFollow           `json:"followed_authors"`
+	Shelves                 []Shelf                  `json:"shelves"`
+	ShelfBooks              []ShelfBook              `json:"shelf_books"`
+}
+
+// DeleteAccountRequest confirms a self-service deletion. Code is required
+// when two-factor authentication is enabled.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+const (
+	ScopeBooksWrite = "books:write"
+	ScopeLoansWrite = "loans:write"
+)
+
+// ScopeRoles lists which roles may hold each API key scope.
+var ScopeRoles = map[string][]string{
+	ScopeBooksWrite: {RoleLibrarian, RoleAdmin},
+	ScopeLoansWrite: {RoleLibrarian, RoleAdmin},
+}
+
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"key_prefix" db:"key_prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=books:write loans:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Principal is the authenticated caller of a request, resolved either from
+// an access token or from an API key.
+type Principal struct {
+	UserID                 int
+	Username               string
+	Role                   string
+	EmailVerified          bool
+	TwoFactorSetupRequired bool
+	TokenID                string
+	TokenExpiresAt         time.Time
+	SessionID              int
+	APIKeyID               int
+	Scopes                 []string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+const (
+	AuthEventRegister               = "register"
+	AuthEventLogin                  = "login"
+	AuthEventTwoFactorChallenge     = "two_factor_challenge"
+	AuthEventTwoFactorLogin         = "two_factor_login"
+	AuthEventOIDCLogin              = "oidc_login"
+	AuthEventTokenRefresh           = "token_refresh"
+	AuthEventRefreshTokenReuse      = "refresh_token_reuse"
+	AuthEventLogout                 = "logout"
+	AuthEventPasswordResetRequested = "password_reset_requested"
+	AuthEventPasswordReset          = "password_reset"
+	AuthEventPasswordChanged        = "password_changed"
+	AuthEventAccessTokenRejected    = "access_token_rejected"
+	AuthEventAPIKeyRejected         = "api_key_rejected"
+	AuthEventAccountDeleted         = "account_deleted"
+)
+
+const (
+	AuthOutcomeSuccess = "success"
+	AuthOutcomeFailure = "failure"
+)
+
+// AuthEvent is one entry of the authentication audit log. UserID is empty
+// when the attempt could not be tied to an account; Username then holds what
+// the client sent, if anything.
+type AuthEvent struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    *int      `json:"user_id" db:"user_id"`
+	Username  string    `json:"username" db:"username"`
+	Type      string    `json:"event_type" db:"event_type"`
+	Outcome   string    `json:"outcome" db:"outcome"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	IPAddress string    `json:"ip_address" db:"ip_address"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type AuthEventQueryParams struct {
+	Page      int       `form:"page,default=1"`
+	Limit     int       `form:"limit,default=50"`
+	UserID    int       `form:"user_id"`
+	Username  string    `form:"username"`
+	Type      string    `form:"event_type"`
+	Outcome   string    `form:"outcome" binding:"omitempty,oneof=success failure"`
+	IPAddress string    `form:"ip_address"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type AuthEventListResponse struct {
+	Events     []AuthEvent `json:"events"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"total_pages"`
+}
@@ -13,6 +13,24 @@ type Book struct {
 	Synopsis   string    `json:"synopsis" db:"synopsis"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Copy counts; lost copies are not counted as owned.
+	AvailableCopies int `json:"available_copies" db:"available_copies"`
+	TotalCopies     int `json:"total_copies" db:"total_copies"`
+}
+
+const (
+	BookEventCreated = "book.created"
+	BookEventUpdated = "book.updated"
+	BookEventDeleted = "book.deleted"
+)
+
+// BookEvent describes a change to the catalog. Book is the book after the
+// change, or as it was before it was deleted.
+type BookEvent struct {
+	Type       string    `json:"type"`
+	Book       Book      `json:"book"`
+	OccurredAt time.Time `json:"occurred_at"`
 }
 
 type CreateBookRequest struct {
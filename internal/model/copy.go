@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusOnHold    = "on_hold"
+	CopyStatusLost      = "lost"
+	CopyStatusRepair    = "repair"
+)
+
+const (
+	CopyConditionNew     = "new"
+	CopyConditionGood    = "good"
+	CopyConditionFair    = "fair"
+	CopyConditionPoor    = "poor"
+	CopyConditionDamaged = "damaged"
+)
+
+// BookCopy is one physical copy of a book, identified by its barcode.
+type BookCopy struct {
+	ID            int        `json:"id" db:"id"`
+	BookID        int        `json:"book_id" db:"book_id"`
+	Barcode       string     `json:"barcode" db:"barcode"`
+	AcquiredAt    *time.Time `json:"acquired_at" db:"acquired_at"`
+	Condition     string     `json:"condition" db:"copy_condition"`
+	ShelfLocation string     `json:"shelf_location" db:"shelf_location"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBookCopyRequest adds a copy. AcquiredAt is a date (YYYY-MM-DD); new
+// copies start as available unless another status is given.
+type CreateBookCopyRequest struct {
+	Barcode       string `json:"barcode" binding:"required,max=64"`
+	AcquiredAt    string `json:"acquired_at" binding:"omitempty,datetime=2006-01-02"`
+	Condition     string `json:"condition" binding:"omitempty,oneof=new good fair poor damaged"`
+	ShelfLocation string `json:"shelf_location" binding:"max=100"`
+	Status        string `json:"status" binding:"omitempty,oneof=available lost repair"`
+}
+
+// UpdateBookCopyRequest changes the given fields. The on_loan status is only
+// set by checkouts and cannot be assigned here.
+type UpdateBookCopyRequest struct {
+	Barcode       string  `json:"barcode" binding:"max=64"`
+	AcquiredAt    string  `json:"acquired_at" binding:"omitempty,datetime=2006-01-02"`
+	Condition     string  `json:"condition" binding:"omitempty,oneof=new good fair poor damaged"`
+	ShelfLocation *string `json:"shelf_location" binding:"omitempty,max=100"`
+	Status        string  `json:"status" binding:"omitempty,oneof=available lost repair"`
+}
+
+type BookCopyQueryParams struct {
+	Status string `form:"status" binding:"omitempty,oneof=available on_loan on_hold lost repair"`
+}
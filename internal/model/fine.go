@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+const (
+	FineStatusOutstanding = "outstanding"
+	FineStatusPaid        = "paid"
+	FineStatusWaived      = "waived"
+)
+
+// Fine is a late return charge in the user's ledger, assessed once when the
+// loan is returned. Amounts are in whole rupiah.
+type Fine struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      int        `json:"user_id" db:"user_id"`
+	LoanID      *int       `json:"loan_id" db:"loan_id"`
+	Barcode     string     `json:"barcode" db:"barcode"`
+	BookTitle   string     `json:"book_title" db:"book_title"`
+	Amount      int        `json:"amount" db:"amount"`
+	DaysOverdue int        `json:"days_overdue" db:"days_overdue"`
+	Status      string     `json:"status" db:"status"`
+	Note        string     `json:"note,omitempty" db:"note"`
+	ResolvedAt  *time.Time `json:"resolved_at" db:"resolved_at"`
+	ResolvedBy  *int       `json:"resolved_by,omitempty" db:"resolved_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AccruingFine is the fine an overdue loan still out would be charged if
+// it were returned now.
+type AccruingFine struct {
+	Loan        Loan `json:"loan"`
+	Amount      int  `json:"amount"`
+	DaysOverdue int  `json:"days_overdue"`
+}
+
+// FineLedger sums up what a user owes. Balance counts outstanding fines and
+// fines still accruing on overdue loans.
+type FineLedger struct {
+	UserID             int            `json:"user_id"`
+	Fines              []Fine         `json:"fines"`
+	Accruing           []AccruingFine `json:"accruing"`
+	OutstandingBalance int            `json:"outstanding_balance"`
+	AccruingBalance    int            `json:"accruing_balance"`
+	Balance            int            `json:"balance"`
+	BorrowingBlocked   bool           `json:"borrowing_blocked"`
+}
+
+type WaiveFineRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type SettleFineRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+type FineQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	UserID int    `form:"user_id"`
+	Status string `form:"status" binding:"omitempty,oneof=outstanding paid waived"`
+}
+
+type FineListResponse struct {
+	Fines      []Fine `json:"fines"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
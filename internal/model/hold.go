@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+// Hold is a member's place in the queue for a book. Once a copy comes back
+// it is set aside on the hold shelf for the first waiting member, and the
+// hold becomes ready until it is picked up or the pickup deadline passes.
+type Hold struct {
+	ID             int        `json:"id" db:"id"`
+	BookID         int        `json:"book_id" db:"book_id"`
+	BookTitle      string     `json:"book_title" db:"book_title"`
+	UserID         int        `json:"user_id" db:"user_id"`
+	Status         string     `json:"status" db:"status"`
+	CopyID         *int       `json:"copy_id" db:"copy_id"`
+	Barcode        string     `json:"barcode,omitempty" db:"barcode"`
+	QueuePosition  int        `json:"queue_position,omitempty" db:"queue_position"`
+	ReadyAt        *time.Time `json:"ready_at" db:"ready_at"`
+	PickupDeadline *time.Time `json:"pickup_deadline" db:"pickup_deadline"`
+	ClosedAt       *time.Time `json:"closed_at" db:"closed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (h *Hold) IsOpen() bool {
+	return h.Status == HoldStatusWaiting || h.Status == HoldStatusReady
+}
+
+type PlaceHoldRequest struct {
+	BookID int `json:"book_id" binding:"required"`
+}
+
+type HoldQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	UserID int    `form:"user_id"`
+	BookID int    `form:"book_id"`
+	Status string `form:"status" binding:"omitempty,oneof=waiting ready fulfilled cancelled expired"`
+}
+
+type HoldListResponse struct {
+	Holds      []Hold `json:"holds"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider.
+type UserIdentity struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       *string    `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// OIDCLoginState is the server-side half of an authorization request, looked
+// up by the state parameter when the provider redirects back.
+type OIDCLoginState struct {
+	StateHash    string    `json:"-"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"-"`
+	Nonce        string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `form:"code" json:"code" binding:"required"`
+	State string `form:"state" json:"state" binding:"required"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// ScheduledJob is a background job and the outcome of its last run. The
+// row is shared by all replicas; whichever claims it first runs it.
+type ScheduledJob struct {
+	Name           string     `json:"name" db:"name"`
+	Schedule       string     `json:"schedule" db:"schedule"`
+	NextRunAt      time.Time  `json:"next_run_at" db:"next_run_at"`
+	Running        bool       `json:"running"`
+	LockedBy       *string    `json:"locked_by,omitempty" db:"locked_by"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	LastStartedAt  *time.Time `json:"last_started_at" db:"last_started_at"`
+	LastFinishedAt *time.Time `json:"last_finished_at" db:"last_finished_at"`
+	LastStatus     string     `json:"last_status,omitempty" db:"last_status"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	LastResult     string     `json:"last_result,omitempty" db:"last_result"`
+	LastDurationMs *int       `json:"last_duration_ms" db:"last_duration_ms"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
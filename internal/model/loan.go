@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+const (
+	LoanStatusActive   = "active"
+	LoanStatusReturned = "returned"
+	LoanStatusOverdue  = "overdue"
+)
+
+// Loan is one checkout of a copy. CopyID and BookID become empty when the
+// copy or book is deleted later. The barcode is kept on the loan so the
+// history still names the copy; BookTitle is then empty.
+type Loan struct {
+	ID            int        `json:"id" db:"id"`
+	CopyID        *int       `json:"copy_id" db:"copy_id"`
+	BookID        *int       `json:"book_id" db:"book_id"`
+	UserID        int        `json:"user_id" db:"user_id"`
+	Barcode       string     `json:"barcode" db:"barcode"`
+	BookTitle     string     `json:"book_title" db:"book_title"`
+	CheckedOutAt  time.Time  `json:"checked_out_at" db:"checked_out_at"`
+	DueAt         time.Time  `json:"due_at" db:"due_at"`
+	RenewalCount  int        `json:"renewal_count" db:"renewal_count"`
+	LastRenewedAt *time.Time `json:"last_renewed_at" db:"last_renewed_at"`
+	ReturnedAt    *time.Time `json:"returned_at" db:"returned_at"`
+	CheckedOutBy  *int       `json:"checked_out_by,omitempty" db:"checked_out_by"`
+	ReturnedBy    *int       `json:"returned_by,omitempty" db:"returned_by"`
+	Overdue       bool       `json:"overdue" db:"-"`
+}
+
+func (l *Loan) IsActive() bool {
+	return l.ReturnedAt == nil
+}
+
+// IsOverdue reports whether the loan is still out past its due date.
+func (l *Loan) IsOverdue(now time.Time) bool {
+	return l.IsActive() && now.After(l.DueAt)
+}
+
+// CheckoutRequest lends a copy, identified by ID or barcode, to a user.
+type CheckoutRequest struct {
+	UserID  int    `json:"user_id" binding:"required"`
+	CopyID  int    `json:"copy_id" binding:"required_without=Barcode"`
+	Barcode string `json:"barcode" binding:"required_without=CopyID"`
+}
+
+type ReturnRequest struct {
+	Barcode string `json:"barcode" binding:"required"`
+}
+
+// ReturnResponse reports a check-in. Fine is set when the copy came back
+// late, Hold when it went to the hold shelf for the next member in the
+// queue instead of back on the shelf.
+type ReturnResponse struct {
+	Loan *Loan `json:"loan"`
+	Fine *Fine `json:"fine"`
+	Hold *Hold `json:"hold"`
+}
+
+// Reasons a renewal can be refused
+const (
+	RenewalRefusedMaxRenewals = "max_renewals_reached"
+	RenewalRefusedOverdue     = "overdue_limit_exceeded"
+	RenewalRefusedOnHold      = "on_hold_for_another_member"
+	RenewalRefusedFines       = "fine_balance_exceeded"
+)
+
+// RenewalRefusal explains one reason a renewal was refused.
+type RenewalRefusal struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+type LoanQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	UserID int    `form:"user_id"`
+	BookID int    `form:"book_id"`
+	CopyID int    `form:"copy_id"`
+	Status string `form:"status" binding:"omitempty,oneof=active returned overdue"`
+}
+
+type LoanListResponse struct {
+	Loans      []Loan `json:"loans"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
+}
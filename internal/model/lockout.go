@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	LoginKeyUsername = "username"
+	LoginKeyIP       = "ip"
+)
+
+type LoginAttempt struct {
+	KeyType      string     `json:"key_type" db:"key_type"`
+	KeyValue     string     `json:"key_value" db:"key_value"`
+	FailedCount  int        `json:"failed_count" db:"failed_count"`
+	LastFailedAt time.Time  `json:"last_failed_at" db:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+}
+
+type AccountLockout struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      *int       `json:"user_id" db:"user_id"`
+	Username    string     `json:"username" db:"username"`
+	IPAddress   string     `json:"ip_address" db:"ip_address"`
+	FailedCount int        `json:"failed_count" db:"failed_count"`
+	LockedUntil time.Time  `json:"locked_until" db:"locked_until"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty" db:"unlocked_at"`
+	UnlockedBy  *int       `json:"unlocked_by,omitempty" db:"unlocked_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type LockoutQueryParams struct {
+	Page       int  `form:"page,default=1"`
+	Limit      int  `form:"limit,default=20"`
+	ActiveOnly bool `form:"active"`
+}
+
+type LockoutListResponse struct {
+	Lockouts   []AccountLockout `json:"lockouts"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	TotalPages int              `json:"total_pages"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import "time"
+
+const (
+	NotificationTypeDueReminder   = "due_reminder"
+	NotificationTypeOverdueNotice = "overdue_notice"
+	NotificationTypeHoldReady     = "hold_ready"
+	NotificationTypeNewBook       = "new_book"
+)
+
+// NotificationTypes lists every notification type with its default
+// channels, used when the user has not set a preference.
+var NotificationTypes = []NotificationPreference{
+	{Type: NotificationTypeDueReminder, InApp: true, Email: true},
+	{Type: NotificationTypeOverdueNotice, InApp: true, Email: true},
+	{Type: NotificationTypeHoldReady, InApp: true, Email: true},
+	{Type: NotificationTypeNewBook, InApp: true, Email: false},
+}
+
+const (
+	NotificationStatusRead   = "read"
+	NotificationStatusUnread = "unread"
+)
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+type Notification struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	Type      string     `json:"type" db:"type"`
+	Title     string     `json:"title" db:"title"`
+	Body      string     `json:"body" db:"body"`
+	BookID    *int       `json:"book_id" db:"book_id"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	InApp         bool    `json:"-" db:"in_app"`
+	EmailStatus   *string `json:"-" db:"email_status"`
+	EmailAttempts int     `json:"-" db:"email_attempts"`
+}
+
+// NotificationEmail is a queued notification email with the recipient.
+type NotificationEmail struct {
+	Notification
+	Email    string
+	FullName string
+	Deleted  bool
+}
+
+// NotificationPreference sets the channels a notification type is
+// delivered on.
+type NotificationPreference struct {
+	Type  string `json:"type" db:"type"`
+	InApp bool   `json:"in_app" db:"in_app"`
+	Email bool   `json:"email" db:"email"`
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	InApp *bool `json:"in_app"`
+	Email *bool `json:"email"`
+}
+
+type NotificationQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	Status string `form:"status" binding:"omitempty,oneof=read unread"`
+}
+
+type NotificationListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	Total         int            `json:"total"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	TotalPages    int            `json:"total_pages"`
+}
+
+type AuthorFollow struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Author    string    `json:"author" db:"author"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type FollowAuthorRequest struct {
+	Author string `json:"author" binding:"required,max=255"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Session groups the tokens issued for one login on one device. Refresh
+// tokens rotate within a session; revoking it ends all of them at once.
+type Session struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Current    bool       `json:"current" db:"-"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ClientInfo describes the device a login request came from.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
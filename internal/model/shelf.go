@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+const (
+	ShelfKindWantToRead = "want_to_read"
+	ShelfKindReading    = "reading"
+	ShelfKindRead       = "read"
+	ShelfKindCustom     = "custom"
+)
+
+// ReadingStatusShelves are created for every user. A book sits on at most
+// one of them at a time.
+var ReadingStatusShelves = []Shelf{
+	{Kind: ShelfKindWantToRead, Name: "Want to Read"},
+	{Kind: ShelfKindReading, Name: "Reading"},
+	{Kind: ShelfKindRead, Name: "Read"},
+}
+
+type Shelf struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      int       `json:"user_id" db:"user_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Kind        string    `json:"kind" db:"kind"`
+	IsPublic    bool      `json:"is_public" db:"is_public"`
+	BookCount   int       `json:"book_count" db:"book_count"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (s *Shelf) IsReadingStatus() bool {
+	return s.Kind != ShelfKindCustom
+}
+
+type CreateShelfRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+type UpdateShelfRequest struct {
+	Name        string  `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+// AddShelfBookRequest puts a book on a shelf. Without a position the book
+// goes to the end of the list.
+type AddShelfBookRequest struct {
+	BookID   int  `json:"book_id" binding:"required"`
+	Position *int `json:"position" binding:"omitempty,min=1"`
+}
+
+// ReorderShelfRequest lists every book on the shelf in the new order.
+type ReorderShelfRequest struct {
+	BookIDs []int `json:"book_ids" binding:"required,min=1"`
+}
+
+type ShelfBook struct {
+	ShelfID  int       `json:"shelf_id" db:"shelf_id"`
+	Position int       `json:"position" db:"position"`
+	AddedAt  time.Time `json:"added_at" db:"added_at"`
+	Book     Book      `json:"book"`
+}
+
+type ShelfBookQueryParams struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+type ShelfBookListResponse struct {
+	Shelf      Shelf       `json:"shelf"`
+	Books      []ShelfBook `json:"books"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// ShelfRef names one of the caller's shelves holding a book.
+type ShelfRef struct {
+	ID       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"name"`
+	Kind     string `json:"kind" db:"kind"`
+	IsPublic bool   `json:"is_public" db:"is_public"`
+}
+
+// BookWithShelves is a book as seen by a signed-in user, with the user's
+// shelves that contain it.
+type BookWithShelves struct {
+	Book
+	Shelves []ShelfRef `json:"shelves"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// MembershipTier sets a patron's borrowing limits. Users without a tier get
+// the default tier.
+type MembershipTier struct {
+	ID             int       `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	Description    string    `json:"description" db:"description"`
+	MaxLoans       int       `json:"max_loans" db:"max_loans"`
+	LoanPeriodDays int       `json:"loan_period_days" db:"loan_period_days"`
+	HoldLimit      int       `json:"hold_limit" db:"hold_limit"`
+	IsDefault      bool      `json:"is_default" db:"is_default"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (t *MembershipTier) LoanPeriod() time.Duration {
+	return time.Duration(t.LoanPeriodDays) * 24 * time.Hour
+}
+
+type CreateMembershipTierRequest struct {
+	Name           string `json:"name" binding:"required,max=50"`
+	Description    string `json:"description" binding:"max=255"`
+	MaxLoans       int    `json:"max_loans" binding:"required,min=1"`
+	LoanPeriodDays int    `json:"loan_period_days" binding:"required,min=1"`
+	HoldLimit      int    `json:"hold_limit" binding:"min=0"`
+	IsDefault      bool   `json:"is_default"`
+}
+
+// UpdateMembershipTierRequest changes the fields that are set. A tier stops
+// being the default only by making another tier the default.
+type UpdateMembershipTierRequest struct {
+	Name           string  `json:"name" binding:"max=50"`
+	Description    *string `json:"description" binding:"omitempty,max=255"`
+	MaxLoans       int     `json:"max_loans" binding:"omitempty,min=1"`
+	LoanPeriodDays int     `json:"loan_period_days" binding:"omitempty,min=1"`
+	HoldLimit      *int    `json:"hold_limit" binding:"omitempty,min=0"`
+	IsDefault      bool    `json:"is_default"`
+}
+
+// UpdateUserTierRequest assigns a tier; a null tier_id puts the user back on
+// the default tier.
+type UpdateUserTierRequest struct {
+	TierID *int `json:"tier_id"`
+}
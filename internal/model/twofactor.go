@@ -0,0 +1,38 @@
+package model
+
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCode          string `json:"qr_code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorActivation struct {
+	RecoveryCodes []string      `json:"recovery_codes"`
+	Tokens        TokenResponse `json:"tokens"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorPolicy struct {
+	RequiredRoles []string `json:"required_roles" binding:"dive,oneof=admin librarian member"`
+}
+
+// SettingTwoFactorRequiredRoles holds a comma-separated list of roles that
+// must enroll in two-factor authentication.
+const SettingTwoFactorRequiredRoles = "two_factor_required_roles"
@@ -2,14 +2,53 @@ package model
 
 import "time"
 
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleMember    = "member"
+)
+
+const (
+	UserStatusActive      = "active"
+	UserStatusDeactivated = "deactivated"
+	UserStatusDeleted     = "deleted"
+)
+
 type User struct {
-	ID        int       `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"`
-	FullName  string    `json:"full_name" db:"full_name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID                    int        `json:"id" db:"id"`
+	Username              string     `json:"username" db:"username"`
+	Email                 string     `json:"email" db:"email"`
+	Password              string     `json:"-" db:"password"`
+	FullName              string     `json:"full_name" db:"full_name"`
+	Role                  string     `json:"role" db:"role"`
+	TierID                *int       `json:"tier_id" db:"tier_id"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at" db:"email_verified_at"`
+	TOTPSecret            *string    `json:"-" db:"totp_secret"`
+	TwoFactorEnabledAt    *time.Time `json:"two_factor_enabled_at" db:"totp_enabled_at"`
+	TOTPLastStep          *int64     `json:"-" db:"totp_last_step"`
+	DeactivatedAt         *time.Time `json:"deactivated_at" db:"deactivated_at"`
+	PasswordResetRequired bool       `json:"password_reset_required" db:"password_reset_required"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil && u.TOTPSecret != nil
+}
+
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// IsDeleted reports whether the account was deleted by its owner or an admin.
+// The row is kept, anonymized, so records referencing it stay valid.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
 }
 
 type LoginRequest struct {
@@ -20,11 +59,50 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	FullName string `json:"full_name" binding:"required"`
 }
 
+// LoginResponse either carries the issued tokens or, when the account has
+// two-factor authentication enabled, only a challenge token that has to be
+// exchanged at /api/auth/login/2fa.
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token                  string     `json:"token,omitempty"`
+	RefreshToken           string     `json:"refresh_token,omitempty"`
+	ExpiresAt              *time.Time `json:"expires_at,omitempty"`
+	User                   *User      `json:"user,omitempty"`
+	TwoFactorRequired      bool       `json:"two_factor_required"`
+	ChallengeToken         string     `json:"challenge_token,omitempty"`
+	TwoFactorSetupRequired bool       `json:"two_factor_setup_required,omitempty"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin librarian member"`
+}
+
+type UpdateProfileRequest struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type UserQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	Search string `form:"search"`
+	Role   string `form:"role" binding:"omitempty,oneof=admin librarian member"`
+	Status string `form:"status" binding:"omitempty,oneof=active deactivated deleted"`
+	TierID int    `form:"tier_id"`
+}
+
+type UserListResponse struct {
+	Users      []User `json:"users"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages int    `json:"total_pages"`
 }
@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+// WebhookEvents lists the events a webhook can subscribe to.
+var WebhookEvents = []string{BookEventCreated, BookEventUpdated, BookEventDeleted}
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+type Webhook struct {
+	ID          int       `json:"id" db:"id"`
+	URL         string    `json:"url" db:"url"`
+	Description string    `json:"description" db:"description"`
+	Events      []string  `json:"events" db:"events"`
+	Secret      string    `json:"-" db:"secret"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedBy   *int      `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1,dive,oneof=book.created book.updated book.deleted"`
+}
+
+// CreateWebhookResponse carries the signing secret, which is only shown
+// when the webhook is created.
+type CreateWebhookResponse struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" binding:"omitempty,url,max=2048"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Events      []string `json:"events" binding:"omitempty,min=1,dive,oneof=book.created book.updated book.deleted"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookPayload is the JSON body posted to webhook endpoints.
+type WebhookPayload struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       WebhookEventData `json:"data"`
+}
+
+type WebhookEventData struct {
+	Book Book `json:"book"`
+}
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of
+// its latest attempt.
+type WebhookDelivery struct {
+	ID             int        `json:"id" db:"id"`
+	WebhookID      int        `json:"webhook_id" db:"webhook_id"`
+	EventID        string     `json:"event_id" db:"event_id"`
+	EventType      string     `json:"event_type" db:"event_type"`
+	Payload        string     `json:"payload" db:"payload"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at" db:"last_attempt_at"`
+	ResponseStatus *int       `json:"response_status" db:"response_status"`
+	ResponseBody   string     `json:"response_body,omitempty" db:"response_body"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at" db:"delivered_at"`
+	ReplayOf       *int       `json:"replay_of" db:"replay_of"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+type WebhookDeliveryQueryParams struct {
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
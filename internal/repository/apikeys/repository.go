@@ -0,0 +1,120 @@
+package apikeys
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateAPIKey(key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, key_prefix, key_hash, scopes, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, key.UserID, key.Name, key.KeyPrefix, key.KeyHash, strings.Join(key.Scopes, ","), key.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	key.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetAPIKeyByHash(keyHash string) (*model.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE key_hash = ?
+	`
+	return scanAPIKey(r.db.QueryRow(query, keyHash))
+}
+
+func (r *Repository) GetAPIKeysByUserID(userID int) ([]model.APIKey, error) {
+	keys := []model.APIKey{}
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes a key owned by userID and reports whether a matching,
+// not yet revoked key was found.
+func (r *Repository) RevokeAPIKey(id, userID int) (bool, error) {
+	query := "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND revoked_at IS NULL"
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// TouchAPIKey records usage. Writes are skipped while the stored value is
+// newer than staleBefore so busy clients do not update the row per request.
+func (r *Repository) TouchAPIKey(id int, usedAt, staleBefore time.Time) error {
+	query := "UPDATE api_keys SET last_used_at = ? WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)"
+	_, err := r.db.Exec(query, usedAt, id, staleBefore)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	var scopes string
+	err := row.Scan(
+		&key.ID, &key.UserID, &key.Name, &key.KeyPrefix, &key.KeyHash, &scopes,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Split(scopes, ",")
+	return key, nil
+}
+
+// DeleteUserAPIKeys removes every API key of a user as part of an account
+// deletion.
+func (r *Repository) DeleteUserAPIKeys(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec("DELETE FROM api_keys WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,165 @@
+package authevents
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateEvent(event *model.AuthEvent) error {
+	query := `
+		INSERT INTO auth_events (user_id, username, event_type, outcome, reason, ip_address, user_agent)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		event.UserID, event.Username, event.Type, event.Outcome,
+		event.Reason, event.IPAddress, event.UserAgent,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	event.ID = id
+	return nil
+}
+
+func (r *Repository) GetEvents(params model.AuthEventQueryParams) ([]model.AuthEvent, int, error) {
+	events := []model.AuthEvent{}
+	var total int
+
+	// Build WHERE clause
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if params.UserID != 0 {
+		whereConditions = append(whereConditions, "user_id = ?")
+		args = append(args, params.UserID)
+	}
+
+	if params.Username != "" {
+		whereConditions = append(whereConditions, "username = ?")
+		args = append(args, params.Username)
+	}
+
+	if params.Type != "" {
+		whereConditions = append(whereConditions, "event_type = ?")
+		args = append(args, params.Type)
+	}
+
+	if params.Outcome != "" {
+		whereConditions = append(whereConditions, "outcome = ?")
+		args = append(args, params.Outcome)
+	}
+
+	if params.IPAddress != "" {
+		whereConditions = append(whereConditions, "ip_address = ?")
+		args = append(args, params.IPAddress)
+	}
+
+	if !params.From.IsZero() {
+		whereConditions = append(whereConditions, "created_at >= ?")
+		args = append(args, params.From)
+	}
+
+	if !params.To.IsZero() {
+		whereConditions = append(whereConditions, "created_at < ?")
+		args = append(args, params.To)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM auth_events %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT id, user_id, username, event_type, outcome, reason, ip_address, user_agent, created_at
+		FROM auth_events %s
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.AuthEvent
+		err := rows.Scan(
+			&event.ID, &event.UserID, &event.Username, &event.Type, &event.Outcome,
+			&event.Reason, &event.IPAddress, &event.UserAgent, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+
+	return events, total, rows.Err()
+}
+
+// GetEventsByUserID returns the whole audit trail of a user, newest first.
+func (r *Repository) GetEventsByUserID(userID int) ([]model.AuthEvent, error) {
+	events := []model.AuthEvent{}
+	query := `
+		SELECT id, user_id, username, event_type, outcome, reason, ip_address, user_agent, created_at
+		FROM auth_events
+		WHERE user_id = ?
+		ORDER BY id DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.AuthEvent
+		err := rows.Scan(
+			&event.ID, &event.UserID, &event.Username, &event.Type, &event.Outcome,
+			&event.Reason, &event.IPAddress, &event.UserAgent, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// AnonymizeUserEvents keeps the audit trail of a deleted account without
+// its username, IP address and user agent, as part of the deletion.
+func (r *Repository) AnonymizeUserEvents(tx *sql.Tx, userID int, username string) error {
+	_, err := tx.Exec(
+		"UPDATE auth_events SET username = '', ip_address = '', user_agent = '' WHERE user_id = ? OR username = ?",
+		userID, username,
+	)
+	return err
+}
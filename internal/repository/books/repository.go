@@ -16,6 +16,12 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// copyCounts selects the copy counts of the book in the outer query.
+const copyCounts = `
+	(SELECT COUNT(*) FROM book_copies c WHERE c.book_id = books.id AND c.status = 'available') AS available_copies,
+	(SELECT COUNT(*) FROM book_copies c WHERE c.book_id = books.id AND c.status != 'lost') AS total_copies
+`
+
 func (r *Repository) CreateBook(book *model.Book) error {
 	query := `
 		INSERT INTO books (title, isbn, year, publisher, author, cover_image, synopsis) 
@@ -37,14 +43,15 @@ func (r *Repository) CreateBook(book *model.Book) error {
 
 func (r *Repository) GetBookByID(id int) (*model.Book, error) {
 	book := &model.Book{}
-	query := `
-		SELECT id, title, isbn, year, publisher, author, cover_image, synopsis, created_at, updated_at 
+	query := fmt.Sprintf(`
+		SELECT id, title, isbn, year, publisher, author, cover_image, synopsis, created_at, updated_at, %s
 		FROM books 
 		WHERE id = ?
-	`
+	`, copyCounts)
 	err := r.db.QueryRow(query, id).Scan(
 		&book.ID, &book.Title, &book.ISBN, &book.Year, &book.Publisher,
 		&book.Author, &book.CoverImage, &book.Synopsis, &book.CreatedAt, &book.UpdatedAt,
+		&book.AvailableCopies, &book.TotalCopies,
 	)
 	if err != nil {
 		return nil, err
@@ -97,11 +104,11 @@ func (r *Repository) GetBooks(params model.BookQueryParams) ([]model.Book, int,
 	// Get paginated results
 	offset := (params.Page - 1) * params.Limit
 	query := fmt.Sprintf(`
-		SELECT id, title, isbn, year, publisher, author, cover_image, synopsis, created_at, updated_at 
+		SELECT id, title, isbn, year, publisher, author, cover_image, synopsis, created_at, updated_at, %s
 		FROM books %s 
 		ORDER BY created_at DESC 
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, copyCounts, whereClause)
 
 	args = append(args, params.Limit, offset)
 	rows, err := r.db.Query(query, args...)
@@ -115,6 +122,7 @@ func (r *Repository) GetBooks(params model.BookQueryParams) ([]model.Book, int,
 		err := rows.Scan(
 			&book.ID, &book.Title, &book.ISBN, &book.Year, &book.Publisher,
 			&book.Author, &book.CoverImage, &book.Synopsis, &book.CreatedAt, &book.UpdatedAt,
+			&book.AvailableCopies, &book.TotalCopies,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -0,0 +1,186 @@
+package copies
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const copyColumns = `
+	id, book_id, barcode, acquired_at, copy_condition, shelf_location, status, created_at, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCopy(row rowScanner) (*model.BookCopy, error) {
+	bookCopy := &model.BookCopy{}
+	err := row.Scan(
+		&bookCopy.ID, &bookCopy.BookID, &bookCopy.Barcode, &bookCopy.AcquiredAt, &bookCopy.Condition,
+		&bookCopy.ShelfLocation, &bookCopy.Status, &bookCopy.CreatedAt, &bookCopy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookCopy, nil
+}
+
+func (r *Repository) CreateCopy(bookCopy *model.BookCopy) error {
+	query := `
+		INSERT INTO book_copies (book_id, barcode, acquired_at, copy_condition, shelf_location, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, bookCopy.BookID, bookCopy.Barcode, bookCopy.AcquiredAt, bookCopy.Condition, bookCopy.ShelfLocation, bookCopy.Status)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	bookCopy.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetCopy(id int) (*model.BookCopy, error) {
+	query := fmt.Sprintf("SELECT %s FROM book_copies WHERE id = ?", copyColumns)
+	return scanCopy(r.db.QueryRow(query, id))
+}
+
+func (r *Repository) GetCopyByBarcode(barcode string) (*model.BookCopy, error) {
+	query := fmt.Sprintf("SELECT %s FROM book_copies WHERE barcode = ?", copyColumns)
+	return scanCopy(r.db.QueryRow(query, barcode))
+}
+
+func (r *Repository) GetCopiesByBookID(bookID int, params model.BookCopyQueryParams) ([]model.BookCopy, error) {
+	copies := []model.BookCopy{}
+
+	whereConditions := []string{"book_id = ?"}
+	args := []interface{}{bookID}
+
+	if params.Status != "" {
+		whereConditions = append(whereConditions, "status = ?")
+		args = append(args, params.Status)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM book_copies
+		WHERE %s
+		ORDER BY barcode
+	`, copyColumns, strings.Join(whereConditions, " AND "))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		bookCopy, err := scanCopy(rows)
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, *bookCopy)
+	}
+
+	return copies, rows.Err()
+}
+
+// UpdateCopy writes the non-empty fields of bookCopy. The shelf location is only
+// written when setShelfLocation is true, so it can be cleared. A status change
+// is only written while the copy still has fromStatus, the status it was
+// decided on; UpdateCopy returns false when the copy moved on meanwhile, for
+// example to a loan.
+func (r *Repository) UpdateCopy(id int, bookCopy *model.BookCopy, setShelfLocation bool, fromStatus string) (bool, error) {
+	setParts := []string{}
+	args := []interface{}{}
+
+	if bookCopy.Barcode != "" {
+		setParts = append(setParts, "barcode = ?")
+		args = append(args, bookCopy.Barcode)
+	}
+
+	if bookCopy.AcquiredAt != nil {
+		setParts = append(setParts, "acquired_at = ?")
+		args = append(args, bookCopy.AcquiredAt)
+	}
+
+	if bookCopy.Condition != "" {
+		setParts = append(setParts, "copy_condition = ?")
+		args = append(args, bookCopy.Condition)
+	}
+
+	if setShelfLocation {
+		setParts = append(setParts, "shelf_location = ?")
+		args = append(args, bookCopy.ShelfLocation)
+	}
+
+	if bookCopy.Status != "" {
+		setParts = append(setParts, "status = ?")
+		args = append(args, bookCopy.Status)
+	}
+
+	if len(setParts) == 0 {
+		return false, fmt.Errorf("no fields to update")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Checked under the row lock rather than through rows affected, which
+	// MySQL reports as 0 when the values did not change
+	if bookCopy.Status != "" {
+		var status string
+		err = tx.QueryRow("SELECT status FROM book_copies WHERE id = ? FOR UPDATE", id).Scan(&status)
+		if err != nil {
+			return false, err
+		}
+		if status != fromStatus {
+			return false, nil
+		}
+	}
+
+	setParts = append(setParts, "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE book_copies SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *Repository) DeleteCopy(id int) error {
+	query := "DELETE FROM book_copies WHERE id = ?"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) CheckBarcodeExists(barcode string, excludeID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM book_copies WHERE barcode = ? AND id != ?"
+	err := r.db.QueryRow(query, barcode, excludeID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,180 @@
+package fines
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const fineColumns = `
+	f.id, f.user_id, f.loan_id, COALESCE(l.barcode, ''), COALESCE(b.title, ''), f.amount, f.days_overdue,
+	f.status, f.note, f.resolved_at, f.resolved_by, f.created_at
+`
+
+const fineTables = `
+	fines f
+	LEFT JOIN loans l ON l.id = f.loan_id
+	LEFT JOIN books b ON b.id = l.book_id
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFine(row rowScanner) (*model.Fine, error) {
+	fine := &model.Fine{}
+	err := row.Scan(
+		&fine.ID, &fine.UserID, &fine.LoanID, &fine.Barcode, &fine.BookTitle, &fine.Amount, &fine.DaysOverdue,
+		&fine.Status, &fine.Note, &fine.ResolvedAt, &fine.ResolvedBy, &fine.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fine, nil
+}
+
+// CreateFine adds the fine as part of tx.
+func (r *Repository) CreateFine(tx *sql.Tx, fine *model.Fine) error {
+	query := `
+		INSERT INTO fines (user_id, loan_id, amount, days_overdue, status)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query, fine.UserID, fine.LoanID, fine.Amount, fine.DaysOverdue, fine.Status)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	fine.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetFine(id int) (*model.Fine, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE f.id = ?", fineColumns, fineTables)
+	return scanFine(r.db.QueryRow(query, id))
+}
+
+func (r *Repository) GetFines(params model.FineQueryParams) ([]model.Fine, int, error) {
+	fines := []model.Fine{}
+	var total int
+
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if params.UserID > 0 {
+		whereConditions = append(whereConditions, "f.user_id = ?")
+		args = append(args, params.UserID)
+	}
+
+	if params.Status != "" {
+		whereConditions = append(whereConditions, "f.status = ?")
+		args = append(args, params.Status)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM fines f %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+		ORDER BY f.created_at DESC, f.id DESC
+		LIMIT ? OFFSET ?
+	`, fineColumns, fineTables, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		fines = append(fines, *fine)
+	}
+
+	return fines, total, rows.Err()
+}
+
+// GetFinesByUserID returns the user's whole fines ledger.
+func (r *Repository) GetFinesByUserID(userID int) ([]model.Fine, error) {
+	fines := []model.Fine{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE f.user_id = ?
+		ORDER BY f.created_at DESC, f.id DESC
+	`, fineColumns, fineTables)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, err
+		}
+		fines = append(fines, *fine)
+	}
+
+	return fines, rows.Err()
+}
+
+func (r *Repository) GetOutstandingBalance(tx *sql.Tx, userID int) (int, error) {
+	var balance int
+	query := "SELECT COALESCE(SUM(amount), 0) FROM fines WHERE user_id = ? AND status = ?"
+	err := tx.QueryRow(query, userID, model.FineStatusOutstanding).Scan(&balance)
+	return balance, err
+}
+
+// ResolveFine marks an outstanding fine as paid or waived. It returns false
+// when the fine was already resolved.
+func (r *Repository) ResolveFine(id int, status, note string, resolvedBy int, resolvedAt time.Time) (bool, error) {
+	query := `
+		UPDATE fines SET status = ?, note = ?, resolved_by = ?, resolved_at = ?
+		WHERE id = ? AND status = ?
+	`
+	result, err := r.db.Exec(query, status, note, resolvedBy, resolvedAt, id, model.FineStatusOutstanding)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
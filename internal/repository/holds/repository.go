@@ -0,0 +1,353 @@
+package holds
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// holdColumns includes the queue position of waiting holds, counted first
+// come first served.
+const holdColumns = `
+	h.id, h.book_id, COALESCE(b.title, ''), h.user_id, h.status, h.copy_id, COALESCE(c.barcode, ''),
+	CASE WHEN h.status = 'waiting' THEN (
+		SELECT COUNT(*) + 1 FROM holds q
+		WHERE q.book_id = h.book_id AND q.status = 'waiting' AND q.id < h.id
+	) ELSE 0 END,
+	h.ready_at, h.pickup_deadline, h.closed_at, h.created_at
+`
+
+const holdTables = `
+	holds h
+	LEFT JOIN books b ON b.id = h.book_id
+	LEFT JOIN book_copies c ON c.id = h.copy_id
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanHold(row rowScanner) (*model.Hold, error) {
+	hold := &model.Hold{}
+	err := row.Scan(
+		&hold.ID, &hold.BookID, &hold.BookTitle, &hold.UserID, &hold.Status, &hold.CopyID, &hold.Barcode,
+		&hold.QueuePosition, &hold.ReadyAt, &hold.PickupDeadline, &hold.ClosedAt, &hold.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (r *Repository) CreateHold(hold *model.Hold) error {
+	query := "INSERT INTO holds (book_id, user_id, status) VALUES (?, ?, ?)"
+	result, err := r.db.Exec(query, hold.BookID, hold.UserID, hold.Status)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	hold.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetHold(id int) (*model.Hold, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE h.id = ?", holdColumns, holdTables)
+	return scanHold(r.db.QueryRow(query, id))
+}
+
+// GetReadyHoldByCopyID returns the hold the copy is set aside for, or
+// sql.ErrNoRows if it is not on the hold shelf.
+func (r *Repository) GetReadyHoldByCopyID(copyID int) (*model.Hold, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE h.copy_id = ? AND h.status = 'ready'", holdColumns, holdTables)
+	return scanHold(r.db.QueryRow(query, copyID))
+}
+
+func (r *Repository) HasOpenHold(userID, bookID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM holds WHERE user_id = ? AND book_id = ? AND status IN ('waiting', 'ready')"
+	err := r.db.QueryRow(query, userID, bookID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) CountOpenHoldsByUserID(userID int) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM holds WHERE user_id = ? AND status IN ('waiting', 'ready')"
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// HasWaitingHolds reports whether anyone other than excludeUserID is
+// queued for the book.
+func (r *Repository) HasWaitingHolds(bookID, excludeUserID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM holds WHERE book_id = ? AND user_id != ? AND status = 'waiting'"
+	err := r.db.QueryRow(query, bookID, excludeUserID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) GetHolds(params model.HoldQueryParams) ([]model.Hold, int, error) {
+	holds := []model.Hold{}
+	var total int
+
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if params.UserID > 0 {
+		whereConditions = append(whereConditions, "h.user_id = ?")
+		args = append(args, params.UserID)
+	}
+
+	if params.BookID > 0 {
+		whereConditions = append(whereConditions, "h.book_id = ?")
+		args = append(args, params.BookID)
+	}
+
+	if params.Status != "" {
+		whereConditions = append(whereConditions, "h.status = ?")
+		args = append(args, params.Status)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM holds h %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+		ORDER BY h.id DESC
+		LIMIT ? OFFSET ?
+	`, holdColumns, holdTables, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		holds = append(holds, *hold)
+	}
+
+	return holds, total, rows.Err()
+}
+
+// GetHoldsByUserID returns every hold the user placed, for data exports.
+func (r *Repository) GetHoldsByUserID(userID int) ([]model.Hold, error) {
+	holds := []model.Hold{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE h.user_id = ?
+		ORDER BY h.id DESC
+	`, holdColumns, holdTables)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, *hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// GetExpiredHolds returns ready holds whose pickup deadline has passed.
+func (r *Repository) GetExpiredHolds(now time.Time) ([]model.Hold, error) {
+	holds := []model.Hold{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE h.status = 'ready' AND h.pickup_deadline < ?
+		ORDER BY h.pickup_deadline
+	`, holdColumns, holdTables)
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, *hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// CloseHold moves an open hold to a final status. It returns false when the
+// hold was already closed.
+func (r *Repository) CloseHold(id int, status string, closedAt time.Time) (bool, error) {
+	query := "UPDATE holds SET status = ?, closed_at = ? WHERE id = ? AND status IN ('waiting', 'ready')"
+	result, err := r.db.Exec(query, status, closedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ShelveCopy hands a copy that just became free to the first waiting hold
+// on its book, moving the copy to the hold shelf. Without waiting holds the
+// copy goes back to available. It returns the ID of the hold that is now
+// ready, or 0.
+func (r *Repository) ShelveCopy(copyID int, readyAt, pickupDeadline time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	holdID, err := r.ShelveCopyTx(tx, copyID, readyAt, pickupDeadline)
+	if err != nil {
+		return 0, err
+	}
+
+	return holdID, tx.Commit()
+}
+
+// ShelveCopyTx is ShelveCopy as part of the caller's transaction.
+func (r *Repository) ShelveCopyTx(tx *sql.Tx, copyID int, readyAt, pickupDeadline time.Time) (int, error) {
+	var bookID int
+	var status string
+	err := tx.QueryRow("SELECT book_id, status FROM book_copies WHERE id = ? FOR UPDATE", copyID).Scan(&bookID, &status)
+	if err != nil {
+		return 0, err
+	}
+	if status != model.CopyStatusAvailable && status != model.CopyStatusOnHold {
+		return 0, nil
+	}
+
+	// Already set aside, e.g. by a concurrent run
+	var readyHolds int
+	err = tx.QueryRow("SELECT COUNT(*) FROM holds WHERE copy_id = ? AND status = 'ready'", copyID).Scan(&readyHolds)
+	if err != nil {
+		return 0, err
+	}
+	if readyHolds > 0 {
+		return 0, nil
+	}
+
+	var holdID int
+	err = tx.QueryRow(`
+		SELECT id FROM holds
+		WHERE book_id = ? AND status = 'waiting'
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE
+	`, bookID).Scan(&holdID)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	if holdID == 0 {
+		_, err = tx.Exec(`
+			UPDATE book_copies SET status = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND status = ?
+		`, model.CopyStatusAvailable, copyID, model.CopyStatusOnHold)
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE holds SET status = 'ready', copy_id = ?, ready_at = ?, pickup_deadline = ?
+		WHERE id = ?
+	`, copyID, readyAt, pickupDeadline, holdID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE book_copies SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, model.CopyStatusOnHold, copyID)
+	if err != nil {
+		return 0, err
+	}
+
+	return holdID, nil
+}
+
+// FulfillHoldTx closes the user's hold on a book they just borrowed, as
+// part of the checkout's transaction. A ready hold for another copy stays
+// open and is passed on when it expires.
+func (r *Repository) FulfillHoldTx(tx *sql.Tx, userID, bookID, copyID int, fulfilledAt time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE holds SET status = ?, closed_at = ?
+		WHERE book_id = ? AND user_id = ?
+		AND (status = ? OR (status = ? AND copy_id = ?))
+	`, model.HoldStatusFulfilled, fulfilledAt, bookID, userID,
+		model.HoldStatusWaiting, model.HoldStatusReady, copyID)
+	return err
+}
+
+// CloseUserHolds takes a user out of every queue as part of an account
+// deletion. Waiting holds are cancelled; copies set aside on the hold shelf
+// are passed on by the next hold expiry run.
+func (r *Repository) CloseUserHolds(tx *sql.Tx, userID int, closedAt time.Time) error {
+	_, err := tx.Exec(
+		"UPDATE holds SET status = ?, closed_at = ? WHERE user_id = ? AND status = ?",
+		model.HoldStatusCancelled, closedAt, userID, model.HoldStatusWaiting,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE holds SET pickup_deadline = ? WHERE user_id = ? AND status = ?",
+		closedAt, userID, model.HoldStatusReady,
+	)
+	return err
+}
@@ -0,0 +1,142 @@
+package identities
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateIdentity(identity *model.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.LastLoginAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	identity.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetIdentity(provider, subject string) (*model.UserIdentity, error) {
+	identity := &model.UserIdentity{}
+	query := `
+		SELECT id, user_id, provider, subject, email, last_login_at, created_at
+		FROM user_identities
+		WHERE provider = ? AND subject = ?
+	`
+	err := r.db.QueryRow(query, provider, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+		&identity.Email, &identity.LastLoginAt, &identity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *Repository) GetIdentitiesByUserID(userID int) ([]model.UserIdentity, error) {
+	identities := []model.UserIdentity{}
+	query := `
+		SELECT id, user_id, provider, subject, email, last_login_at, created_at
+		FROM user_identities
+		WHERE user_id = ?
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var identity model.UserIdentity
+		err := rows.Scan(
+			&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+			&identity.Email, &identity.LastLoginAt, &identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+func (r *Repository) TouchIdentity(id int, email *string, loginAt time.Time) error {
+	query := "UPDATE user_identities SET email = ?, last_login_at = ? WHERE id = ?"
+	_, err := r.db.Exec(query, email, loginAt, id)
+	return err
+}
+
+func (r *Repository) CreateOIDCState(state *model.OIDCLoginState) error {
+	query := `
+		INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, state.StateHash, state.Provider, state.CodeVerifier, state.Nonce, state.ExpiresAt)
+	return err
+}
+
+// ConsumeOIDCState loads and deletes a login state in one step so a callback
+// can never be replayed. It returns sql.ErrNoRows for unknown states.
+func (r *Repository) ConsumeOIDCState(stateHash string) (*model.OIDCLoginState, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	state := &model.OIDCLoginState{}
+	query := `
+		SELECT state_hash, provider, code_verifier, nonce, expires_at, created_at
+		FROM oidc_login_states
+		WHERE state_hash = ?
+		FOR UPDATE
+	`
+	err = tx.QueryRow(query, stateHash).Scan(
+		&state.StateHash, &state.Provider, &state.CodeVerifier, &state.Nonce,
+		&state.ExpiresAt, &state.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("DELETE FROM oidc_login_states WHERE state_hash = ?", stateHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, tx.Commit()
+}
+
+// DeleteExpiredOIDCStates removes abandoned login attempts.
+func (r *Repository) DeleteExpiredOIDCStates(before time.Time) error {
+	_, err := r.db.Exec("DELETE FROM oidc_login_states WHERE expires_at < ?", before)
+	return err
+}
+
+// DeleteUserIdentities unlinks every external identity of a user as part
+// of an account deletion.
+func (r *Repository) DeleteUserIdentities(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec("DELETE FROM user_identities WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const jobColumns = `
+	name, schedule, next_run_at, locked_by, locked_until, last_started_at, last_finished_at,
+	COALESCE(last_status, ''), COALESCE(last_error, ''), COALESCE(last_result, ''), last_duration_ms, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (*model.ScheduledJob, error) {
+	job := &model.ScheduledJob{}
+	err := row.Scan(
+		&job.Name, &job.Schedule, &job.NextRunAt, &job.LockedBy, &job.LockedUntil,
+		&job.LastStartedAt, &job.LastFinishedAt, &job.LastStatus, &job.LastError, &job.LastResult,
+		&job.LastDurationMs, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// UpsertJob registers a job. An existing row keeps its next run unless the
+// schedule changed, so restarting a replica does not reset the timetable.
+func (r *Repository) UpsertJob(name, schedule string, nextRunAt time.Time) error {
+	// next_run_at is assigned first so it still sees the old schedule
+	query := `
+		INSERT INTO scheduled_jobs (name, schedule, next_run_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			next_run_at = IF(schedule = VALUES(schedule), next_run_at, VALUES(next_run_at)),
+			schedule = VALUES(schedule)
+	`
+	_, err := r.db.Exec(query, name, schedule, nextRunAt)
+	return err
+}
+
+// ClaimJob takes the lock on a job that is due at now. It returns false when
+// the job is not due yet or another replica holds an unexpired lock on it.
+func (r *Repository) ClaimJob(name, owner string, now, lockedUntil time.Time) (bool, error) {
+	query := `
+		UPDATE scheduled_jobs SET locked_by = ?, locked_until = ?, last_started_at = ?
+		WHERE name = ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	result, err := r.db.Exec(query, owner, lockedUntil, now, name, now, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ExtendJobLock moves the expiry of a lock owner still holds. It returns
+// false when owner lost the lock meanwhile.
+func (r *Repository) ExtendJobLock(name, owner string, lockedUntil time.Time) (bool, error) {
+	query := "UPDATE scheduled_jobs SET locked_until = ? WHERE name = ? AND locked_by = ?"
+	result, err := r.db.Exec(query, lockedUntil, name, owner)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// FinishJob records the outcome of a run, schedules the next one and
+// releases the lock. Nothing is written if owner lost the lock meanwhile.
+func (r *Repository) FinishJob(name, owner string, job *model.ScheduledJob) error {
+	query := `
+		UPDATE scheduled_jobs
+		SET next_run_at = ?, locked_by = NULL, locked_until = NULL, last_finished_at = ?,
+			last_status = ?, last_error = ?, last_result = ?, last_duration_ms = ?
+		WHERE name = ? AND locked_by = ?
+	`
+	_, err := r.db.Exec(
+		query, job.NextRunAt, job.LastFinishedAt, job.LastStatus, job.LastError, job.LastResult,
+		job.LastDurationMs, name, owner,
+	)
+	return err
+}
+
+// TriggerJob makes a job due immediately.
+func (r *Repository) TriggerJob(name string, now time.Time) error {
+	_, err := r.db.Exec("UPDATE scheduled_jobs SET next_run_at = ? WHERE name = ?", now, name)
+	return err
+}
+
+func (r *Repository) GetJob(name string) (*model.ScheduledJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM scheduled_jobs WHERE name = ?", jobColumns)
+	return scanJob(r.db.QueryRow(query, name))
+}
+
+func (r *Repository) GetJobs() ([]model.ScheduledJob, error) {
+	jobs := []model.ScheduledJob{}
+
+	query := fmt.Sprintf("SELECT %s FROM scheduled_jobs ORDER BY name", jobColumns)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
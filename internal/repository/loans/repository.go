@@ -0,0 +1,359 @@
+package loans
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const loanColumns = `
+	l.id, l.copy_id, l.book_id, l.user_id, l.barcode, COALESCE(b.title, ''),
+	l.checked_out_at, l.due_at, l.renewal_count, l.last_renewed_at, l.returned_at, l.checked_out_by, l.returned_by
+`
+
+const loanTables = `
+	loans l
+	LEFT JOIN books b ON b.id = l.book_id
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLoan(row rowScanner) (*model.Loan, error) {
+	loan := &model.Loan{}
+	err := row.Scan(
+		&loan.ID, &loan.CopyID, &loan.BookID, &loan.UserID, &loan.Barcode, &loan.BookTitle,
+		&loan.CheckedOutAt, &loan.DueAt, &loan.RenewalCount, &loan.LastRenewedAt, &loan.ReturnedAt,
+		&loan.CheckedOutBy, &loan.ReturnedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// CreateLoan marks the copy as on loan and records the loan as part of tx.
+// copyStatus is the status the copy is expected to be in; CreateLoan returns
+// false when it no longer is, for example because another checkout of it
+// just went through.
+func (r *Repository) CreateLoan(tx *sql.Tx, loan *model.Loan, copyStatus string) (bool, error) {
+	result, err := tx.Exec(`
+		UPDATE book_copies SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`, model.CopyStatusOnLoan, loan.CopyID, copyStatus)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	result, err = tx.Exec(`
+		INSERT INTO loans (copy_id, active_copy_id, book_id, user_id, barcode, checked_out_at, due_at, checked_out_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, loan.CopyID, loan.CopyID, loan.BookID, loan.UserID, loan.Barcode, loan.CheckedOutAt, loan.DueAt, loan.CheckedOutBy)
+	if err != nil {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	loan.ID = int(id)
+	return true, nil
+}
+
+// Begin starts a transaction for a change that spans repositories, such
+// as a checkout that fulfills a hold or a return that also fills a hold and
+// charges a fine.
+func (r *Repository) Begin() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+// ReturnLoan closes an active loan and makes its copy available again as
+// part of tx. It returns false when the loan was already returned.
+func (r *Repository) ReturnLoan(tx *sql.Tx, id int, returnedBy int, returnedAt time.Time) (bool, error) {
+	result, err := tx.Exec(`
+		UPDATE loans SET returned_at = ?, returned_by = ?, active_copy_id = NULL
+		WHERE id = ? AND returned_at IS NULL
+	`, returnedAt, returnedBy, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE book_copies c
+		JOIN loans l ON l.copy_id = c.id
+		SET c.status = ?, c.updated_at = CURRENT_TIMESTAMP
+		WHERE l.id = ? AND c.status = ?
+	`, model.CopyStatusAvailable, id, model.CopyStatusOnLoan)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RenewLoan moves the due date of an active loan and counts the renewal.
+// renewalCount is the count the renewal was decided on; RenewLoan returns
+// false when the loan changed in the meantime. Notices already sent are
+// cleared so they go out again for the new due date.
+func (r *Repository) RenewLoan(id, renewalCount int, dueAt, renewedAt time.Time) (bool, error) {
+	query := `
+		UPDATE loans SET due_at = ?, renewal_count = renewal_count + 1, last_renewed_at = ?,
+			reminder_sent_at = NULL, overdue_notice_sent_at = NULL
+		WHERE id = ? AND returned_at IS NULL AND renewal_count = ?
+	`
+	result, err := r.db.Exec(query, dueAt, renewedAt, id, renewalCount)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) GetLoan(id int) (*model.Loan, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE l.id = ?", loanColumns, loanTables)
+	return scanLoan(r.db.QueryRow(query, id))
+}
+
+// GetActiveLoanByCopyID returns the loan the copy is currently out on, or
+// sql.ErrNoRows if it is not on loan.
+func (r *Repository) GetActiveLoanByCopyID(copyID int) (*model.Loan, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE l.copy_id = ? AND l.returned_at IS NULL", loanColumns, loanTables)
+	return scanLoan(r.db.QueryRow(query, copyID))
+}
+
+func (r *Repository) HasActiveLoanForBook(userID, bookID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM loans WHERE user_id = ? AND book_id = ? AND returned_at IS NULL"
+	err := r.db.QueryRow(query, userID, bookID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) CountActiveLoansByUserID(tx *sql.Tx, userID int) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM loans WHERE user_id = ? AND returned_at IS NULL"
+	err := tx.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// GetLoans lists loans newest first. now decides which active loans count
+// as overdue.
+func (r *Repository) GetLoans(params model.LoanQueryParams, now time.Time) ([]model.Loan, int, error) {
+	loans := []model.Loan{}
+	var total int
+
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if params.UserID > 0 {
+		whereConditions = append(whereConditions, "l.user_id = ?")
+		args = append(args, params.UserID)
+	}
+
+	if params.BookID > 0 {
+		whereConditions = append(whereConditions, "l.book_id = ?")
+		args = append(args, params.BookID)
+	}
+
+	if params.CopyID > 0 {
+		whereConditions = append(whereConditions, "l.copy_id = ?")
+		args = append(args, params.CopyID)
+	}
+
+	switch params.Status {
+	case model.LoanStatusActive:
+		whereConditions = append(whereConditions, "l.returned_at IS NULL")
+	case model.LoanStatusReturned:
+		whereConditions = append(whereConditions, "l.returned_at IS NOT NULL")
+	case model.LoanStatusOverdue:
+		whereConditions = append(whereConditions, "l.returned_at IS NULL AND l.due_at < ?")
+		args = append(args, now)
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM loans l %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+		ORDER BY l.checked_out_at DESC, l.id DESC
+		LIMIT ? OFFSET ?
+	`, loanColumns, loanTables, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		loans = append(loans, *loan)
+	}
+
+	return loans, total, rows.Err()
+}
+
+// GetOverdueLoansByUserID returns the user's active loans that were due
+// before now.
+func (r *Repository) GetOverdueLoansByUserID(userID int, now time.Time) ([]model.Loan, error) {
+	loans := []model.Loan{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE l.user_id = ? AND l.returned_at IS NULL AND l.due_at < ?
+		ORDER BY l.due_at
+	`, loanColumns, loanTables)
+
+	rows, err := r.db.Query(query, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, *loan)
+	}
+
+	return loans, rows.Err()
+}
+
+// GetLoansByUserID returns the user's whole loan history, for data exports.
+func (r *Repository) GetLoansByUserID(userID int) ([]model.Loan, error) {
+	loans := []model.Loan{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE l.user_id = ?
+		ORDER BY l.checked_out_at DESC, l.id DESC
+	`, loanColumns, loanTables)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, *loan)
+	}
+
+	return loans, rows.Err()
+}
+
+// GetLoansDueForReminder returns active loans due between now and dueBefore
+// that have not been reminded about yet.
+func (r *Repository) GetLoansDueForReminder(now, dueBefore time.Time) ([]model.Loan, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE l.returned_at IS NULL AND l.reminder_sent_at IS NULL AND l.due_at >= ? AND l.due_at <= ?
+		ORDER BY l.due_at, l.id
+	`, loanColumns, loanTables)
+
+	return r.queryLoans(query, now, dueBefore)
+}
+
+// GetLoansDueForOverdueNotice returns active loans due before now whose
+// borrower has not been notified yet.
+func (r *Repository) GetLoansDueForOverdueNotice(now time.Time) ([]model.Loan, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE l.returned_at IS NULL AND l.overdue_notice_sent_at IS NULL AND l.due_at < ?
+		ORDER BY l.due_at, l.id
+	`, loanColumns, loanTables)
+
+	return r.queryLoans(query, now)
+}
+
+func (r *Repository) queryLoans(query string, args ...interface{}) ([]model.Loan, error) {
+	loans := []model.Loan{}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, *loan)
+	}
+
+	return loans, rows.Err()
+}
+
+func (r *Repository) MarkReminderSent(id int, sentAt time.Time) error {
+	_, err := r.db.Exec("UPDATE loans SET reminder_sent_at = ? WHERE id = ?", sentAt, id)
+	return err
+}
+
+func (r *Repository) MarkOverdueNoticeSent(id int, sentAt time.Time) error {
+	_, err := r.db.Exec("UPDATE loans SET overdue_notice_sent_at = ? WHERE id = ?", sentAt, id)
+	return err
+}
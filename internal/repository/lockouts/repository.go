@@ -0,0 +1,239 @@
+package lockouts
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Begin() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+// LockLoginAttempt returns the failure counter of a key and locks it until
+// tx ends. A key without a counter gets an empty one first, so there is
+// always a row to lock.
+func (r *Repository) LockLoginAttempt(tx *sql.Tx, keyType, keyValue string, now time.Time) (*model.LoginAttempt, error) {
+	_, err := tx.Exec(`
+		INSERT INTO login_attempts (key_type, key_value, failed_count, last_failed_at)
+		VALUES (?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE failed_count = failed_count
+	`, keyType, keyValue, now)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := &model.LoginAttempt{}
+	query := `
+		SELECT key_type, key_value, failed_count, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE key_type = ? AND key_value = ?
+		FOR UPDATE
+	`
+	err = tx.QueryRow(query, keyType, keyValue).Scan(
+		&attempt.KeyType, &attempt.KeyValue, &attempt.FailedCount,
+		&attempt.LastFailedAt, &attempt.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// RecordFailedLogin increments the failure counter of a key locked with
+// LockLoginAttempt and returns the new count. Counters whose last failure
+// happened before windowStart start over from one.
+func (r *Repository) RecordFailedLogin(tx *sql.Tx, keyType, keyValue string, failedAt, windowStart time.Time) (int, error) {
+	query := `
+		UPDATE login_attempts SET
+			failed_count = IF(last_failed_at < ?, 1, failed_count + 1),
+			locked_until = IF(last_failed_at < ?, NULL, locked_until),
+			last_failed_at = ?
+		WHERE key_type = ? AND key_value = ?
+	`
+	_, err := tx.Exec(query, windowStart, windowStart, failedAt, keyType, keyValue)
+	if err != nil {
+		return 0, err
+	}
+
+	var failedCount int
+	err = tx.QueryRow(
+		"SELECT failed_count FROM login_attempts WHERE key_type = ? AND key_value = ?",
+		keyType, keyValue,
+	).Scan(&failedCount)
+	return failedCount, err
+}
+
+// ReleaseFailedLogin takes back one failure from a key's counter.
+func (r *Repository) ReleaseFailedLogin(keyType, keyValue string) error {
+	query := "UPDATE login_attempts SET failed_count = failed_count - 1 WHERE key_type = ? AND key_value = ? AND failed_count > 0"
+	_, err := r.db.Exec(query, keyType, keyValue)
+	return err
+}
+
+func (r *Repository) LockLoginKey(keyType, keyValue string, lockedUntil time.Time) error {
+	query := "UPDATE login_attempts SET locked_until = ? WHERE key_type = ? AND key_value = ?"
+	_, err := r.db.Exec(query, lockedUntil, keyType, keyValue)
+	return err
+}
+
+func (r *Repository) ResetLoginAttempts(keyType, keyValue string) error {
+	query := "DELETE FROM login_attempts WHERE key_type = ? AND key_value = ?"
+	_, err := r.db.Exec(query, keyType, keyValue)
+	return err
+}
+
+func (r *Repository) CreateLockout(lockout *model.AccountLockout) error {
+	query := `
+		INSERT INTO account_lockouts (user_id, username, ip_address, failed_count, locked_until)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, lockout.UserID, lockout.Username, lockout.IPAddress, lockout.FailedCount, lockout.LockedUntil)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	lockout.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetLockouts(params model.LockoutQueryParams, now time.Time) ([]model.AccountLockout, int, error) {
+	var lockouts []model.AccountLockout
+	var total int
+
+	whereClause := ""
+	args := []interface{}{}
+	if params.ActiveOnly {
+		whereClause = "WHERE unlocked_at IS NULL AND locked_until > ?"
+		args = append(args, now)
+	}
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM account_lockouts %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT id, user_id, username, ip_address, failed_count, locked_until, unlocked_at, unlocked_by, created_at
+		FROM account_lockouts %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lockout model.AccountLockout
+		err := rows.Scan(
+			&lockout.ID, &lockout.UserID, &lockout.Username, &lockout.IPAddress, &lockout.FailedCount,
+			&lockout.LockedUntil, &lockout.UnlockedAt, &lockout.UnlockedBy, &lockout.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		lockouts = append(lockouts, lockout)
+	}
+
+	return lockouts, total, nil
+}
+
+func (r *Repository) GetLockoutsByUserID(userID int) ([]model.AccountLockout, error) {
+	lockouts := []model.AccountLockout{}
+	query := `
+		SELECT id, user_id, username, ip_address, failed_count, locked_until, unlocked_at, unlocked_by, created_at
+		FROM account_lockouts
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lockout model.AccountLockout
+		err := rows.Scan(
+			&lockout.ID, &lockout.UserID, &lockout.Username, &lockout.IPAddress, &lockout.FailedCount,
+			&lockout.LockedUntil, &lockout.UnlockedAt, &lockout.UnlockedBy, &lockout.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lockouts = append(lockouts, lockout)
+	}
+
+	return lockouts, rows.Err()
+}
+
+// UnlockUsername clears the failure counter of a username and marks its open
+// lockout events as resolved by the given admin.
+func (r *Repository) UnlockUsername(username string, unlockedBy int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"DELETE FROM login_attempts WHERE key_type = ? AND key_value = ?",
+		model.LoginKeyUsername, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE account_lockouts SET unlocked_at = CURRENT_TIMESTAMP, unlocked_by = ? WHERE username = ? AND unlocked_at IS NULL",
+		unlockedBy, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AnonymizeUserLockouts clears the failure counter of a deleted account's
+// username and keeps its lockout events without the username and IP
+// address, as part of the deletion.
+func (r *Repository) AnonymizeUserLockouts(tx *sql.Tx, userID int, username string) error {
+	_, err := tx.Exec(
+		"DELETE FROM login_attempts WHERE key_type = ? AND key_value = ?",
+		model.LoginKeyUsername, username,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE account_lockouts SET username = '', ip_address = '' WHERE user_id = ? OR username = ?",
+		userID, username,
+	)
+	return err
+}
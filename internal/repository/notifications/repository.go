@@ -0,0 +1,348 @@
+package notifications
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const notificationColumns = `
+	n.id, n.user_id, n.type, n.title, n.body, n.book_id, n.read_at, n.created_at,
+	n.in_app, n.email_status, n.email_attempts
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanNotification(row rowScanner, extra ...interface{}) (*model.Notification, error) {
+	notification := &model.Notification{}
+	dest := []interface{}{
+		&notification.ID, &notification.UserID, &notification.Type, &notification.Title, &notification.Body,
+		&notification.BookID, &notification.ReadAt, &notification.CreatedAt,
+		&notification.InApp, &notification.EmailStatus, &notification.EmailAttempts,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	notification.Read = notification.ReadAt != nil
+	return notification, nil
+}
+
+func (r *Repository) CreateNotification(notification *model.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, title, body, book_id, in_app, email_status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(
+		query, notification.UserID, notification.Type, notification.Title, notification.Body,
+		notification.BookID, notification.InApp, notification.EmailStatus,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	notification.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetNotification(id int) (*model.Notification, error) {
+	query := fmt.Sprintf("SELECT %s FROM notifications n WHERE n.id = ?", notificationColumns)
+	return scanNotification(r.db.QueryRow(query, id))
+}
+
+// GetNotifications lists the user's inbox, newest first.
+func (r *Repository) GetNotifications(userID int, params model.NotificationQueryParams) ([]model.Notification, int, error) {
+	notifications := []model.Notification{}
+	var total int
+
+	whereConditions := []string{"n.user_id = ?", "n.in_app = TRUE"}
+	args := []interface{}{userID}
+
+	switch params.Status {
+	case model.NotificationStatusRead:
+		whereConditions = append(whereConditions, "n.read_at IS NOT NULL")
+	case model.NotificationStatusUnread:
+		whereConditions = append(whereConditions, "n.read_at IS NULL")
+	}
+
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM notifications n %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notifications n
+		%s
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT ? OFFSET ?
+	`, notificationColumns, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return notifications, total, rows.Err()
+}
+
+// GetNotificationsByUserID returns every notification of the user, including
+// email-only ones, for data exports.
+func (r *Repository) GetNotificationsByUserID(userID int) ([]model.Notification, error) {
+	notifications := []model.Notification{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notifications n
+		WHERE n.user_id = ?
+		ORDER BY n.created_at DESC, n.id DESC
+	`, notificationColumns)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *Repository) CountUnread(userID int) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND in_app = TRUE AND read_at IS NULL"
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) MarkRead(id int, readAt time.Time) error {
+	_, err := r.db.Exec("UPDATE notifications SET read_at = ? WHERE id = ? AND read_at IS NULL", readAt, id)
+	return err
+}
+
+func (r *Repository) MarkAllRead(userID int, readAt time.Time) error {
+	query := "UPDATE notifications SET read_at = ? WHERE user_id = ? AND in_app = TRUE AND read_at IS NULL"
+	_, err := r.db.Exec(query, readAt, userID)
+	return err
+}
+
+// GetPendingEmails returns up to limit notifications waiting to be emailed,
+// oldest first, with their recipient.
+func (r *Repository) GetPendingEmails(limit int) ([]model.NotificationEmail, error) {
+	emails := []model.NotificationEmail{}
+
+	query := fmt.Sprintf(`
+		SELECT %s, u.email, u.full_name, u.deleted_at IS NOT NULL
+		FROM notifications n
+		JOIN users u ON u.id = n.user_id
+		WHERE n.email_status = ?
+		ORDER BY n.id
+		LIMIT ?
+	`, notificationColumns)
+
+	rows, err := r.db.Query(query, model.EmailStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var email model.NotificationEmail
+		notification, err := scanNotification(rows, &email.Email, &email.FullName, &email.Deleted)
+		if err != nil {
+			return nil, err
+		}
+		email.Notification = *notification
+		emails = append(emails, email)
+	}
+
+	return emails, rows.Err()
+}
+
+// RecordEmailAttempt counts a delivery attempt and sets the email status.
+// emailedAt is set when the email went out.
+func (r *Repository) RecordEmailAttempt(id int, status string, emailedAt *time.Time) error {
+	query := `
+		UPDATE notifications
+		SET email_status = ?, email_attempts = email_attempts + 1, emailed_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, status, emailedAt, id)
+	return err
+}
+
+// GetPreferences returns the preferences the user has set. Types without a
+// row use their defaults.
+func (r *Repository) GetPreferences(userID int) ([]model.NotificationPreference, error) {
+	preferences := []model.NotificationPreference{}
+
+	rows, err := r.db.Query("SELECT type, in_app, email FROM notification_preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var preference model.NotificationPreference
+		if err := rows.Scan(&preference.Type, &preference.InApp, &preference.Email); err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+func (r *Repository) UpsertPreference(userID int, preference model.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, type, in_app, email)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE in_app = VALUES(in_app), email = VALUES(email)
+	`
+	_, err := r.db.Exec(query, userID, preference.Type, preference.InApp, preference.Email)
+	return err
+}
+
+func (r *Repository) CreateFollow(follow *model.AuthorFollow) error {
+	result, err := r.db.Exec("INSERT INTO author_follows (user_id, author) VALUES (?, ?)", follow.UserID, follow.Author)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	follow.ID = int(id)
+	return nil
+}
+
+func (r *Repository) CheckFollowExists(userID int, author string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM author_follows WHERE user_id = ? AND author = ?"
+	err := r.db.QueryRow(query, userID, author).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) GetFollowsByUserID(userID int) ([]model.AuthorFollow, error) {
+	follows := []model.AuthorFollow{}
+
+	query := "SELECT id, user_id, author, created_at FROM author_follows WHERE user_id = ? ORDER BY author"
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var follow model.AuthorFollow
+		if err := rows.Scan(&follow.ID, &follow.UserID, &follow.Author, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+// GetFollowerIDsByAuthor returns the active users following the author.
+// Names are compared with the column collation, so case does not matter.
+func (r *Repository) GetFollowerIDsByAuthor(author string) ([]int, error) {
+	userIDs := []int{}
+
+	query := `
+		SELECT f.user_id
+		FROM author_follows f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.author = ? AND u.deactivated_at IS NULL AND u.deleted_at IS NULL
+	`
+	rows, err := r.db.Query(query, author)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// DeleteFollow removes one of the user's follows. It returns false when the
+// user has no follow with that ID.
+func (r *Repository) DeleteFollow(id, userID int) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM author_follows WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteUserData removes the notifications, preferences and author follows
+// of a user as part of an account deletion.
+func (r *Repository) DeleteUserData(tx *sql.Tx, userID int) error {
+	statements := []string{
+		"DELETE FROM notifications WHERE user_id = ?",
+		"DELETE FROM notification_preferences WHERE user_id = ?",
+		"DELETE FROM author_follows WHERE user_id = ?",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
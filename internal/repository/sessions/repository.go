@@ -0,0 +1,184 @@
+package sessions
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSession(session *model.Session) error {
+	query := `
+		INSERT INTO sessions (user_id, user_agent, ip_address, expires_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt, session.LastSeenAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	session.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetSession(id int) (*model.Session, error) {
+	session := &model.Session{}
+	query := `
+		SELECT id, user_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at
+		FROM sessions
+		WHERE id = ?
+	`
+	err := r.db.QueryRow(query, id).Scan(
+		&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.ExpiresAt, &session.LastSeenAt, &session.RevokedAt, &session.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *Repository) GetActiveSessionsByUserID(userID int, now time.Time) ([]model.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at
+		FROM sessions
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY last_seen_at DESC, id DESC
+	`
+	return r.querySessions(query, userID, now)
+}
+
+// GetSessionsByUserID returns every session of the user, including revoked
+// and expired ones.
+func (r *Repository) GetSessionsByUserID(userID int) ([]model.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at
+		FROM sessions
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	return r.querySessions(query, userID)
+}
+
+func (r *Repository) querySessions(query string, args ...interface{}) ([]model.Session, error) {
+	sessions := []model.Session{}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session model.Session
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.ExpiresAt, &session.LastSeenAt, &session.RevokedAt, &session.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// TouchSession records activity. Writes are skipped while the stored value is
+// newer than staleBefore so every request does not update the row.
+func (r *Repository) TouchSession(id int, seenAt, staleBefore time.Time) error {
+	query := "UPDATE sessions SET last_seen_at = ? WHERE id = ? AND last_seen_at < ?"
+	_, err := r.db.Exec(query, seenAt, id, staleBefore)
+	return err
+}
+
+// ExtendSession moves the expiry forward after a refresh token rotation.
+func (r *Repository) ExtendSession(id int, expiresAt, seenAt time.Time) error {
+	query := "UPDATE sessions SET expires_at = ?, last_seen_at = ? WHERE id = ?"
+	_, err := r.db.Exec(query, expiresAt, seenAt, id)
+	return err
+}
+
+// RevokeSession revokes a session owned by userID together with its refresh
+// tokens and reports whether an active session was found.
+func (r *Repository) RevokeSession(id, userID int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		id, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(
+		"UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE session_id = ? AND revoked_at IS NULL",
+		id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RevokeUserSessions revokes every session of a user except exceptID (0 keeps
+// none), plus all refresh tokens outside the kept session, including legacy
+// tokens that belong to no session.
+func (r *Repository) RevokeUserSessions(userID, exceptID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND id <> ? AND revoked_at IS NULL",
+		userID, exceptID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND (session_id IS NULL OR session_id <> ?) AND revoked_at IS NULL",
+		userID, exceptID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteUserSessions removes every session of a user as part of an account
+// deletion.
+func (r *Repository) DeleteUserSessions(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,34 @@
+package settings
+
+import (
+	"database/sql"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// GetSetting returns the stored value, or sql.ErrNoRows if it was never set.
+func (r *Repository) GetSetting(name string) (string, error) {
+	var value string
+	query := "SELECT value FROM settings WHERE name = ?"
+	err := r.db.QueryRow(query, name).Scan(&value)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (r *Repository) SetSetting(name, value string, updatedBy int) error {
+	query := `
+		INSERT INTO settings (name, value, updated_by)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE value = ?, updated_by = ?
+	`
+	_, err := r.db.Exec(query, name, value, updatedBy, value, updatedBy)
+	return err
+}
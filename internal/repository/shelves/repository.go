@@ -0,0 +1,425 @@
+package shelves
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const shelfColumns = `
+	shelves.id, shelves.user_id, shelves.name, shelves.description, shelves.kind, shelves.is_public,
+	(SELECT COUNT(*) FROM shelf_books sb WHERE sb.shelf_id = shelves.id) AS book_count,
+	shelves.created_at, shelves.updated_at
+`
+
+// shelfBookColumns selects a shelf entry with its book; copy counts
+// refer to the joined books row.
+const shelfBookColumns = `
+	shelf_books.shelf_id, shelf_books.position, shelf_books.added_at,
+	books.id, books.title, books.isbn, books.year, books.publisher, books.author, books.cover_image,
+	books.synopsis, books.created_at, books.updated_at,
+	(SELECT COUNT(*) FROM book_copies c WHERE c.book_id = books.id AND c.status = 'available') AS available_copies,
+	(SELECT COUNT(*) FROM book_copies c WHERE c.book_id = books.id AND c.status != 'lost') AS total_copies
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShelf(row rowScanner) (*model.Shelf, error) {
+	shelf := &model.Shelf{}
+	err := row.Scan(
+		&shelf.ID, &shelf.UserID, &shelf.Name, &shelf.Description, &shelf.Kind, &shelf.IsPublic,
+		&shelf.BookCount, &shelf.CreatedAt, &shelf.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return shelf, nil
+}
+
+func scanShelfBook(row rowScanner) (*model.ShelfBook, error) {
+	entry := &model.ShelfBook{}
+	book := &entry.Book
+	err := row.Scan(
+		&entry.ShelfID, &entry.Position, &entry.AddedAt,
+		&book.ID, &book.Title, &book.ISBN, &book.Year, &book.Publisher, &book.Author, &book.CoverImage,
+		&book.Synopsis, &book.CreatedAt, &book.UpdatedAt, &book.AvailableCopies, &book.TotalCopies,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// EnsureReadingStatusShelves creates the reading status shelves the user
+// does not have yet.
+func (r *Repository) EnsureReadingStatusShelves(userID int) error {
+	values := []string{}
+	args := []interface{}{}
+	for _, shelf := range model.ReadingStatusShelves {
+		values = append(values, "(?, ?, ?)")
+		args = append(args, userID, shelf.Name, shelf.Kind)
+	}
+
+	query := fmt.Sprintf("INSERT IGNORE INTO shelves (user_id, name, kind) VALUES %s", strings.Join(values, ", "))
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+func (r *Repository) CreateShelf(shelf *model.Shelf) error {
+	query := `
+		INSERT INTO shelves (user_id, name, description, kind, is_public)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, shelf.UserID, shelf.Name, shelf.Description, shelf.Kind, shelf.IsPublic)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	shelf.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetShelf(id int) (*model.Shelf, error) {
+	query := fmt.Sprintf("SELECT %s FROM shelves WHERE shelves.id = ?", shelfColumns)
+	return scanShelf(r.db.QueryRow(query, id))
+}
+
+// GetShelvesByUserID lists the user's shelves, reading status shelves
+// first. With publicOnly set, private shelves are left out.
+func (r *Repository) GetShelvesByUserID(userID int, publicOnly bool) ([]model.Shelf, error) {
+	shelves := []model.Shelf{}
+
+	whereClause := "WHERE shelves.user_id = ?"
+	if publicOnly {
+		whereClause += " AND shelves.is_public = TRUE"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM shelves
+		%s
+		ORDER BY shelves.kind = ?, shelves.id
+	`, shelfColumns, whereClause)
+
+	rows, err := r.db.Query(query, userID, model.ShelfKindCustom)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		shelf, err := scanShelf(rows)
+		if err != nil {
+			return nil, err
+		}
+		shelves = append(shelves, *shelf)
+	}
+
+	return shelves, rows.Err()
+}
+
+// GetShelvesContainingBook returns the user's shelves that hold the book.
+func (r *Repository) GetShelvesContainingBook(userID, bookID int) ([]model.ShelfRef, error) {
+	refs := []model.ShelfRef{}
+
+	query := `
+		SELECT shelves.id, shelves.name, shelves.kind, shelves.is_public
+		FROM shelves
+		JOIN shelf_books ON shelf_books.shelf_id = shelves.id
+		WHERE shelves.user_id = ? AND shelf_books.book_id = ?
+		ORDER BY shelves.kind = ?, shelves.id
+	`
+	rows, err := r.db.Query(query, userID, bookID, model.ShelfKindCustom)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ref model.ShelfRef
+		if err := rows.Scan(&ref.ID, &ref.Name, &ref.Kind, &ref.IsPublic); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+func (r *Repository) UpdateShelf(id int, req model.UpdateShelfRequest) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	if req.Name != "" {
+		setParts = append(setParts, "name = ?")
+		args = append(args, req.Name)
+	}
+
+	if req.Description != nil {
+		setParts = append(setParts, "description = ?")
+		args = append(args, *req.Description)
+	}
+
+	if req.IsPublic != nil {
+		setParts = append(setParts, "is_public = ?")
+		args = append(args, *req.IsPublic)
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE shelves SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteShelf removes the shelf along with its entries.
+func (r *Repository) DeleteShelf(id int) error {
+	_, err := r.db.Exec("DELETE FROM shelves WHERE id = ?", id)
+	return err
+}
+
+func (r *Repository) CheckNameExists(userID int, name string, excludeID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM shelves WHERE user_id = ? AND name = ? AND id != ?"
+	err := r.db.QueryRow(query, userID, name, excludeID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetShelfBooks lists a page of the shelf's books in shelf order.
+func (r *Repository) GetShelfBooks(shelfID int, params model.ShelfBookQueryParams) ([]model.ShelfBook, int, error) {
+	var total int
+
+	err := r.db.QueryRow("SELECT COUNT(*) FROM shelf_books WHERE shelf_id = ?", shelfID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM shelf_books
+		JOIN books ON books.id = shelf_books.book_id
+		WHERE shelf_books.shelf_id = ?
+		ORDER BY shelf_books.position, shelf_books.added_at
+		LIMIT ? OFFSET ?
+	`, shelfBookColumns)
+
+	entries, err := r.queryShelfBooks(query, shelfID, params.Limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// GetShelfBooksByUserID returns the entries of all the user's shelves.
+func (r *Repository) GetShelfBooksByUserID(userID int) ([]model.ShelfBook, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM shelf_books
+		JOIN books ON books.id = shelf_books.book_id
+		JOIN shelves ON shelves.id = shelf_books.shelf_id
+		WHERE shelves.user_id = ?
+		ORDER BY shelf_books.shelf_id, shelf_books.position
+	`, shelfBookColumns)
+
+	return r.queryShelfBooks(query, userID)
+}
+
+func (r *Repository) queryShelfBooks(query string, args ...interface{}) ([]model.ShelfBook, error) {
+	entries := []model.ShelfBook{}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanShelfBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetShelfBookIDs returns the IDs of the books on the shelf in shelf order.
+func (r *Repository) GetShelfBookIDs(shelfID int) ([]int, error) {
+	ids := []int{}
+
+	rows, err := r.db.Query("SELECT book_id FROM shelf_books WHERE shelf_id = ? ORDER BY position, added_at", shelfID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *Repository) CheckBookOnShelf(shelfID, bookID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM shelf_books WHERE shelf_id = ? AND book_id = ?"
+	err := r.db.QueryRow(query, shelfID, bookID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddBook puts the book on the shelf at position, shifting the books from
+// there on down, or at the end when position is nil or past the end. The
+// book is taken off the shelves in removeFrom in the same transaction.
+func (r *Repository) AddBook(shelfID, bookID int, position *int, removeFrom []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, otherID := range removeFrom {
+		if _, err := removeBook(tx, otherID, bookID); err != nil {
+			return err
+		}
+	}
+
+	if err := lockShelf(tx, shelfID); err != nil {
+		return err
+	}
+
+	var last int
+	err = tx.QueryRow("SELECT COALESCE(MAX(position), 0) FROM shelf_books WHERE shelf_id = ?", shelfID).Scan(&last)
+	if err != nil {
+		return err
+	}
+
+	at := last + 1
+	if position != nil && *position < at {
+		at = *position
+		_, err = tx.Exec("UPDATE shelf_books SET position = position + 1 WHERE shelf_id = ? AND position >= ?", shelfID, at)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO shelf_books (shelf_id, book_id, position) VALUES (?, ?, ?)", shelfID, bookID, at)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveBook takes the book off the shelf and closes the gap it leaves.
+// It returns false when the book was not on the shelf.
+func (r *Repository) RemoveBook(shelfID, bookID int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	removed, err := removeBook(tx, shelfID, bookID)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ReorderBooks renumbers the shelf in the order of bookIDs, which must
+// hold every book on the shelf.
+func (r *Repository) ReorderBooks(shelfID int, bookIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockShelf(tx, shelfID); err != nil {
+		return err
+	}
+
+	for i, bookID := range bookIDs {
+		_, err := tx.Exec("UPDATE shelf_books SET position = ? WHERE shelf_id = ? AND book_id = ?", i+1, shelfID, bookID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lockShelf serializes position changes on a shelf until tx ends.
+func lockShelf(tx *sql.Tx, shelfID int) error {
+	var id int
+	return tx.QueryRow("SELECT id FROM shelves WHERE id = ? FOR UPDATE", shelfID).Scan(&id)
+}
+
+func removeBook(tx *sql.Tx, shelfID, bookID int) (bool, error) {
+	if err := lockShelf(tx, shelfID); err != nil {
+		return false, err
+	}
+
+	var position int
+	err := tx.QueryRow("SELECT position FROM shelf_books WHERE shelf_id = ? AND book_id = ?", shelfID, bookID).Scan(&position)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM shelf_books WHERE shelf_id = ? AND book_id = ?", shelfID, bookID); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec("UPDATE shelf_books SET position = position - 1 WHERE shelf_id = ? AND position > ?", shelfID, position)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteUserShelves removes every shelf of a user, with the books on them,
+// as part of an account deletion.
+func (r *Repository) DeleteUserShelves(tx *sql.Tx, userID int) error {
+	_, err := tx.Exec("DELETE FROM shelves WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,192 @@
+package tiers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const tierColumns = `
+	id, name, description, max_loans, loan_period_days, hold_limit, is_default, created_at, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTier(row rowScanner) (*model.MembershipTier, error) {
+	tier := &model.MembershipTier{}
+	err := row.Scan(
+		&tier.ID, &tier.Name, &tier.Description, &tier.MaxLoans, &tier.LoanPeriodDays,
+		&tier.HoldLimit, &tier.IsDefault, &tier.CreatedAt, &tier.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+// CreateTier inserts the tier. When it is the default, the previous default
+// tier stops being one in the same transaction.
+func (r *Repository) CreateTier(tier *model.MembershipTier) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if tier.IsDefault {
+		if _, err := tx.Exec("UPDATE membership_tiers SET is_default = FALSE WHERE is_default = TRUE"); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO membership_tiers (name, description, max_loans, loan_period_days, hold_limit, is_default)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, tier.Name, tier.Description, tier.MaxLoans, tier.LoanPeriodDays, tier.HoldLimit, tier.IsDefault)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	tier.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetTier(id int) (*model.MembershipTier, error) {
+	query := fmt.Sprintf("SELECT %s FROM membership_tiers WHERE id = ?", tierColumns)
+	return scanTier(r.db.QueryRow(query, id))
+}
+
+// GetDefaultTier returns sql.ErrNoRows when no tier is marked as default.
+func (r *Repository) GetDefaultTier() (*model.MembershipTier, error) {
+	query := fmt.Sprintf("SELECT %s FROM membership_tiers WHERE is_default = TRUE LIMIT 1", tierColumns)
+	return scanTier(r.db.QueryRow(query))
+}
+
+func (r *Repository) GetTiers() ([]model.MembershipTier, error) {
+	tiers := []model.MembershipTier{}
+
+	query := fmt.Sprintf("SELECT %s FROM membership_tiers ORDER BY name", tierColumns)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tier, err := scanTier(rows)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, *tier)
+	}
+
+	return tiers, rows.Err()
+}
+
+// UpdateTier writes the non-empty fields of tier. Description and hold
+// limit are only written when their set flags are true, so they can be
+// cleared. Making the tier the default unsets the previous default.
+func (r *Repository) UpdateTier(id int, tier *model.MembershipTier, setDescription, setHoldLimit bool) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	if tier.Name != "" {
+		setParts = append(setParts, "name = ?")
+		args = append(args, tier.Name)
+	}
+
+	if setDescription {
+		setParts = append(setParts, "description = ?")
+		args = append(args, tier.Description)
+	}
+
+	if tier.MaxLoans > 0 {
+		setParts = append(setParts, "max_loans = ?")
+		args = append(args, tier.MaxLoans)
+	}
+
+	if tier.LoanPeriodDays > 0 {
+		setParts = append(setParts, "loan_period_days = ?")
+		args = append(args, tier.LoanPeriodDays)
+	}
+
+	if setHoldLimit {
+		setParts = append(setParts, "hold_limit = ?")
+		args = append(args, tier.HoldLimit)
+	}
+
+	if tier.IsDefault {
+		setParts = append(setParts, "is_default = TRUE")
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if tier.IsDefault {
+		if _, err := tx.Exec("UPDATE membership_tiers SET is_default = FALSE WHERE is_default = TRUE AND id != ?", id); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE membership_tiers SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) DeleteTier(id int) error {
+	query := "DELETE FROM membership_tiers WHERE id = ?"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) CheckNameExists(name string, excludeID int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM membership_tiers WHERE name = ? AND id != ?"
+	err := r.db.QueryRow(query, name, excludeID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) CountUsersByTierID(id int) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE tier_id = ?"
+	err := r.db.QueryRow(query, id).Scan(&count)
+	return count, err
+}
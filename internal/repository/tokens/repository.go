@@ -0,0 +1,276 @@
+package tokens
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateRefreshToken(token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	token.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error) {
+	token := &model.RefreshToken{}
+	query := `
+		SELECT id, user_id, session_id, token_hash, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+	`
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.SessionID, &token.TokenHash, &token.ExpiresAt,
+		&token.RevokedAt, &token.ReplacedBy, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken revokes the old token and links it to its replacement.
+// It reports false when the old token had already been revoked, which means
+// a concurrent request rotated it first.
+func (r *Repository) RotateRefreshToken(oldID int, newToken *model.RefreshToken) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL",
+		oldID,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	result, err = tx.Exec(
+		"INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at) VALUES (?, ?, ?, ?)",
+		newToken.UserID, newToken.SessionID, newToken.TokenHash, newToken.ExpiresAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	newToken.ID = int(id)
+
+	_, err = tx.Exec("UPDATE refresh_tokens SET replaced_by = ? WHERE id = ?", newToken.ID, oldID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *Repository) RevokeRefreshToken(id int) error {
+	query := "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) RevokeAccessToken(jti string, userID int, expiresAt time.Time) error {
+	query := `
+		INSERT IGNORE INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES (?, ?, ?)
+	`
+	_, err := r.db.Exec(query, jti, userID, expiresAt)
+	return err
+}
+
+func (r *Repository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM revoked_tokens WHERE jti = ?"
+	err := r.db.QueryRow(query, jti).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) CreatePasswordReset(reset *model.PasswordReset) error {
+	query := `
+		INSERT INTO password_resets (user_id, token_hash, expires_at)
+		VALUES (?, ?, ?)
+	`
+	result, err := r.db.Exec(query, reset.UserID, reset.TokenHash, reset.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	reset.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetPasswordResetByHash(tokenHash string) (*model.PasswordReset, error) {
+	reset := &model.PasswordReset{}
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_resets
+		WHERE token_hash = ?
+	`
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&reset.ID, &reset.UserID, &reset.TokenHash, &reset.ExpiresAt,
+		&reset.UsedAt, &reset.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reset, nil
+}
+
+// MarkPasswordResetUsed consumes a reset token as part of tx. It reports
+// false when the token was already used, so a token can never be redeemed
+// twice.
+func (r *Repository) MarkPasswordResetUsed(tx *sql.Tx, id int) (bool, error) {
+	query := "UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE id = ? AND used_at IS NULL"
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) InvalidateUserPasswordResets(userID int) error {
+	query := "UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND used_at IS NULL"
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// ReplaceRecoveryCodes discards all existing recovery codes of a user and
+// stores the given hashes in their place.
+func (r *Repository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.Exec("INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?)", userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode consumes an unused recovery code and reports whether one
+// matched.
+func (r *Repository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	query := "UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *Repository) DeleteRecoveryCodes(userID int) error {
+	query := "DELETE FROM recovery_codes WHERE user_id = ?"
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// DeleteExpiredRefreshTokens removes refresh tokens that expired before the
+// given time; they can no longer be redeemed or replayed.
+func (r *Repository) DeleteExpiredRefreshTokens(before time.Time) (int, error) {
+	return r.deleteExpired("DELETE FROM refresh_tokens WHERE expires_at < ?", before)
+}
+
+// DeleteExpiredRevokedTokens removes denylist entries for access tokens that
+// have expired anyway.
+func (r *Repository) DeleteExpiredRevokedTokens(before time.Time) (int, error) {
+	return r.deleteExpired("DELETE FROM revoked_tokens WHERE expires_at < ?", before)
+}
+
+func (r *Repository) DeleteExpiredPasswordResets(before time.Time) (int, error) {
+	return r.deleteExpired("DELETE FROM password_resets WHERE expires_at < ?", before)
+}
+
+func (r *Repository) deleteExpired(query string, before time.Time) (int, error) {
+	result, err := r.db.Exec(query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// DeleteUserTokens removes every refresh token, revoked token, password
+// reset and recovery code of a user as part of an account deletion.
+func (r *Repository) DeleteUserTokens(tx *sql.Tx, userID int) error {
+	statements := []string{
+		"DELETE FROM refresh_tokens WHERE user_id = ?",
+		"DELETE FROM revoked_tokens WHERE user_id = ?",
+		"DELETE FROM password_resets WHERE user_id = ?",
+		"DELETE FROM recovery_codes WHERE user_id = ?",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package users
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/go-sql-driver/mysql"
 )
 
 type Repository struct {
@@ -14,12 +18,65 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// mysqlDuplicateEntry is the MySQL error number for a unique key violation.
+const mysqlDuplicateEntry = 1062
+
+// DuplicateField returns the unique column, "username" or "email", that a
+// write failed on, or "" when err is not a duplicate key error. It catches
+// the races that a lookup before the write cannot.
+func DuplicateField(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return ""
+	}
+
+	// MySQL 8 names the key as 'users.email', older versions as 'email'
+	for _, field := range []string{"username", "email"} {
+		if strings.HasSuffix(mysqlErr.Message, "'"+field+"'") || strings.HasSuffix(mysqlErr.Message, "'users."+field+"'") {
+			return field
+		}
+	}
+	return ""
+}
+
+const userColumns = `
+	id, username, email, password, full_name, role, tier_id, email_verified_at,
+	totp_secret, totp_enabled_at, totp_last_step, deactivated_at,
+	password_reset_required, deleted_at, created_at, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*model.User, error) {
+	user := &model.User{}
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Password,
+		&user.FullName, &user.Role, &user.TierID, &user.EmailVerifiedAt,
+		&user.TOTPSecret, &user.TwoFactorEnabledAt, &user.TOTPLastStep,
+		&user.DeactivatedAt, &user.PasswordResetRequired, &user.DeletedAt,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// getUserBy looks a user up by a unique column. column is never user input.
+func (r *Repository) getUserBy(column string, value interface{}) (*model.User, error) {
+	query := fmt.Sprintf("SELECT %s FROM users WHERE %s = ?", userColumns, column)
+	return scanUser(r.db.QueryRow(query, value))
+}
+
 func (r *Repository) CreateUser(user *model.User) error {
 	query := `
-		INSERT INTO users (username, email, password, full_name) 
-		VALUES (?, ?, ?, ?)
+		INSERT INTO users (username, email, password, full_name, role, email_verified_at) 
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query, user.Username, user.Email, user.Password, user.FullName)
+	result, err := r.db.Exec(query, user.Username, user.Email, user.Password, user.FullName, user.Role, user.EmailVerifiedAt)
 	if err != nil {
 		return err
 	}
@@ -34,37 +91,246 @@ func (r *Repository) CreateUser(user *model.User) error {
 }
 
 func (r *Repository) GetUserByUsername(username string) (*model.User, error) {
-	user := &model.User{}
-	query := `
-		SELECT id, username, email, password, full_name, created_at, updated_at 
-		FROM users 
-		WHERE username = ?
-	`
-	err := r.db.QueryRow(query, username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password,
-		&user.FullName, &user.CreatedAt, &user.UpdatedAt,
-	)
+	return r.getUserBy("username", username)
+}
+
+func (r *Repository) GetUserByID(id int) (*model.User, error) {
+	return r.getUserBy("id", id)
+}
+
+func (r *Repository) UpdateUserRole(id int, role string) error {
+	query := "UPDATE users SET role = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, role, id)
+	return err
+}
+
+// UpdateUserTier assigns a membership tier; nil means the default tier.
+func (r *Repository) UpdateUserTier(id int, tierID *int) error {
+	query := "UPDATE users SET tier_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, tierID, id)
+	return err
+}
+
+func (r *Repository) GetUsers(params model.UserQueryParams) ([]model.User, int, error) {
+	users := []model.User{}
+	var total int
+
+	// Build WHERE clause
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, "(username LIKE ? OR email LIKE ? OR full_name LIKE ?)")
+		searchTerm := "%" + params.Search + "%"
+		args = append(args, searchTerm, searchTerm, searchTerm)
+	}
+
+	if params.Role != "" {
+		whereConditions = append(whereConditions, "role = ?")
+		args = append(args, params.Role)
+	}
+
+	if params.TierID > 0 {
+		whereConditions = append(whereConditions, "tier_id = ?")
+		args = append(args, params.TierID)
+	}
+
+	switch params.Status {
+	case model.UserStatusActive:
+		whereConditions = append(whereConditions, "deactivated_at IS NULL")
+	case model.UserStatusDeactivated:
+		whereConditions = append(whereConditions, "deactivated_at IS NOT NULL AND deleted_at IS NULL")
+	case model.UserStatusDeleted:
+		whereConditions = append(whereConditions, "deleted_at IS NOT NULL")
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM users %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return user, nil
+	// Get paginated results
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM users %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, userColumns, whereClause)
+
+	args = append(args, params.Limit, offset)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, total, rows.Err()
 }
 
-func (r *Repository) GetUserByID(id int) (*model.User, error) {
-	user := &model.User{}
-	query := `
-		SELECT id, username, email, password, full_name, created_at, updated_at 
-		FROM users 
-		WHERE id = ?
-	`
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password,
-		&user.FullName, &user.CreatedAt, &user.UpdatedAt,
-	)
+// CountActiveUsersByRole counts users with the role, ignoring deactivated
+// accounts.
+func (r *Repository) CountActiveUsersByRole(role string) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE role = ? AND deactivated_at IS NULL"
+	err := r.db.QueryRow(query, role).Scan(&count)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	return count, nil
+}
 
-	return user, nil
+func (r *Repository) DeactivateUser(id int) error {
+	query := "UPDATE users SET deactivated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deactivated_at IS NULL"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) ReactivateUser(id int) error {
+	query := "UPDATE users SET deactivated_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// RequirePasswordReset blocks password logins until the user sets a new
+// password through the reset flow.
+func (r *Repository) RequirePasswordReset(id int) error {
+	query := "UPDATE users SET password_reset_required = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) GetUserByEmail(email string) (*model.User, error) {
+	return r.getUserBy("email", email)
+}
+
+// Begin starts a transaction for a change that spans repositories, such as
+// a password reset that also uses up its token or an account deletion.
+func (r *Repository) Begin() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+const updatePasswordQuery = "UPDATE users SET password = ?, password_reset_required = FALSE, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+
+// UpdateUserPassword also clears a pending forced reset, since the user has
+// now chosen a new password.
+func (r *Repository) UpdateUserPassword(id int, hashedPassword string) error {
+	_, err := r.db.Exec(updatePasswordQuery, hashedPassword, id)
+	return err
+}
+
+// UpdateUserPasswordTx is UpdateUserPassword as part of tx.
+func (r *Repository) UpdateUserPasswordTx(tx *sql.Tx, id int, hashedPassword string) error {
+	_, err := tx.Exec(updatePasswordQuery, hashedPassword, id)
+	return err
+}
+
+func (r *Repository) MarkEmailVerified(id int) error {
+	query := "UPDATE users SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND email_verified_at IS NULL"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *Repository) UpdateUserProfile(id int, user *model.User) error {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+
+	if user.FullName != "" {
+		setParts = append(setParts, "full_name = ?")
+		args = append(args, user.FullName)
+	}
+
+	if user.Email != "" {
+		// A new address has to be verified again
+		setParts = append(setParts, "email = ?", "email_verified_at = NULL")
+		args = append(args, user.Email)
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// SetTOTPSecret stores a new pending secret. Two-factor authentication stays
+// disabled until EnableTOTP is called after the first valid code.
+func (r *Repository) SetTOTPSecret(id int, secret string) error {
+	query := "UPDATE users SET totp_secret = ?, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, secret, id)
+	return err
+}
+
+func (r *Repository) EnableTOTP(id int, step int64) error {
+	query := "UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP, totp_last_step = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, step, id)
+	return err
+}
+
+func (r *Repository) DisableTOTP(id int) error {
+	query := "UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// UseTOTPStep records the time step of an accepted code. It reports false if
+// that step (or a later one) was already used, which blocks code replay.
+func (r *Repository) UseTOTPStep(id int, step int64) (bool, error) {
+	query := "UPDATE users SET totp_last_step = ? WHERE id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)"
+	result, err := r.db.Exec(query, step, id, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// LockUser locks the row of a user until tx ends and returns the username,
+// or sql.ErrNoRows when the account is deleted. Account deletion and
+// checkout both take this lock, so a loan cannot be made between the
+// deletion's checks and the anonymization.
+func (r *Repository) LockUser(tx *sql.Tx, id int) (string, error) {
+	var username string
+	err := tx.QueryRow("SELECT username FROM users WHERE id = ? AND deleted_at IS NULL FOR UPDATE", id).Scan(&username)
+	return username, err
+}
+
+// AnonymizeUser replaces the personal data of a user locked with LockUser
+// with the given placeholders as part of tx. The row itself is kept so
+// records that must outlive the account still point at a valid user.
+func (r *Repository) AnonymizeUser(tx *sql.Tx, id int, username, email, password string) error {
+	_, err := tx.Exec(`
+		UPDATE users
+		SET username = ?, email = ?, password = ?, full_name = 'Deleted User',
+			email_verified_at = NULL, totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL,
+			password_reset_required = FALSE, deactivated_at = COALESCE(deactivated_at, CURRENT_TIMESTAMP),
+			deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, username, email, password, id)
+	return err
 }
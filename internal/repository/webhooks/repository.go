@@ -0,0 +1,333 @@
+package webhooks
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for a unique key violation.
+const mysqlDuplicateEntry = 1062
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+const webhookColumns = `
+	id, url, description, events, secret, is_active, created_by, created_at, updated_at
+`
+
+const deliveryColumns = `
+	id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+	response_status, COALESCE(response_body, ''), COALESCE(last_error, ''), delivered_at, replay_of, created_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (*model.Webhook, error) {
+	webhook := &model.Webhook{}
+	var events string
+	err := row.Scan(
+		&webhook.ID, &webhook.URL, &webhook.Description, &events, &webhook.Secret, &webhook.IsActive,
+		&webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Events = strings.Split(events, ",")
+	return webhook, nil
+}
+
+func scanDelivery(row rowScanner) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{}
+	err := row.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+		&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastAttemptAt,
+		&delivery.ResponseStatus, &delivery.ResponseBody, &delivery.LastError, &delivery.DeliveredAt,
+		&delivery.ReplayOf, &delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *Repository) CreateWebhook(webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, description, events, secret, is_active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(
+		query, webhook.URL, webhook.Description, strings.Join(webhook.Events, ","), webhook.Secret,
+		webhook.IsActive, webhook.CreatedBy,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = int(id)
+	return nil
+}
+
+func (r *Repository) GetWebhook(id int) (*model.Webhook, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhooks WHERE id = ?", webhookColumns)
+	return scanWebhook(r.db.QueryRow(query, id))
+}
+
+func (r *Repository) GetWebhooks() ([]model.Webhook, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhooks ORDER BY id", webhookColumns)
+	return r.queryWebhooks(query)
+}
+
+func (r *Repository) GetActiveWebhooks() ([]model.Webhook, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhooks WHERE is_active = TRUE ORDER BY id", webhookColumns)
+	return r.queryWebhooks(query)
+}
+
+func (r *Repository) queryWebhooks(query string, args ...interface{}) ([]model.Webhook, error) {
+	webhooks := []model.Webhook{}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *Repository) UpdateWebhook(id int, req model.UpdateWebhookRequest) error {
+	setParts := []string{}
+	args := []interface{}{}
+
+	if req.URL != "" {
+		setParts = append(setParts, "url = ?")
+		args = append(args, req.URL)
+	}
+
+	if req.Description != nil {
+		setParts = append(setParts, "description = ?")
+		args = append(args, *req.Description)
+	}
+
+	if len(req.Events) > 0 {
+		setParts = append(setParts, "events = ?")
+		args = append(args, strings.Join(req.Events, ","))
+	}
+
+	if req.IsActive != nil {
+		setParts = append(setParts, "is_active = ?")
+		args = append(args, *req.IsActive)
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE webhooks SET %s WHERE id = ?", strings.Join(setParts, ", "))
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteWebhook removes the webhook along with its delivery log.
+func (r *Repository) DeleteWebhook(id int) error {
+	_, err := r.db.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	return err
+}
+
+func (r *Repository) CreateDelivery(delivery *model.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, replay_of)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(
+		query, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Status,
+		delivery.NextAttemptAt, delivery.ReplayOf,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = int(id)
+	return nil
+}
+
+// CreateReplay adds a delivery that replays delivery.ReplayOf. It returns
+// false when that delivery was already replayed, so concurrent replays of
+// the same delivery queue only one.
+func (r *Repository) CreateReplay(delivery *model.WebhookDelivery) (bool, error) {
+	err := r.CreateDelivery(delivery)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HasReplay reports whether the delivery was already replayed.
+func (r *Repository) HasReplay(id int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM webhook_deliveries WHERE replay_of = ?)", id).Scan(&exists)
+	return exists, err
+}
+
+func (r *Repository) GetDelivery(id int) (*model.WebhookDelivery, error) {
+	query := fmt.Sprintf("SELECT %s FROM webhook_deliveries WHERE id = ?", deliveryColumns)
+	return scanDelivery(r.db.QueryRow(query, id))
+}
+
+// GetDeliveries lists a webhook's delivery log, newest first.
+func (r *Repository) GetDeliveries(webhookID int, params model.WebhookDeliveryQueryParams) ([]model.WebhookDelivery, int, error) {
+	var total int
+
+	whereConditions := []string{"webhook_id = ?"}
+	args := []interface{}{webhookID}
+
+	if params.Status != "" {
+		whereConditions = append(whereConditions, "status = ?")
+		args = append(args, params.Status)
+	}
+
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM webhook_deliveries %s", whereClause)
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, deliveryColumns, whereClause)
+
+	args = append(args, params.Limit, offset)
+	deliveries, err := r.queryDeliveries(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// GetFailedDeliveries returns the webhook's deliveries that gave up and
+// were not replayed yet, oldest first.
+func (r *Repository) GetFailedDeliveries(webhookID int) ([]model.WebhookDelivery, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries d
+		WHERE webhook_id = ? AND status = ?
+			AND NOT EXISTS (SELECT 1 FROM webhook_deliveries r WHERE r.replay_of = d.id)
+		ORDER BY id
+	`, deliveryColumns)
+
+	return r.queryDeliveries(query, webhookID, model.DeliveryStatusFailed)
+}
+
+// GetDueDeliveries returns up to limit pending deliveries whose next
+// attempt is due at now and that no worker holds.
+func (r *Repository) GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`, deliveryColumns)
+
+	return r.queryDeliveries(query, model.DeliveryStatusPending, now, now, limit)
+}
+
+func (r *Repository) queryDeliveries(query string, args ...interface{}) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// ClaimDelivery locks a due pending delivery for one attempt. It returns
+// false when the delivery is not due or another worker holds it.
+func (r *Repository) ClaimDelivery(id int, now, lockedUntil time.Time) (bool, error) {
+	query := `
+		UPDATE webhook_deliveries SET locked_until = ?
+		WHERE id = ? AND status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	result, err := r.db.Exec(query, lockedUntil, id, model.DeliveryStatusPending, now, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// RecordAttempt stores the outcome of an attempt and releases the lock.
+func (r *Repository) RecordAttempt(delivery *model.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?, response_status = ?,
+			response_body = ?, last_error = ?, delivered_at = ?, locked_until = NULL
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(
+		query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+		delivery.ResponseStatus, delivery.ResponseBody, delivery.LastError, delivery.DeliveredAt, delivery.ID,
+	)
+	return err
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountDataOwner is a service that keeps records about users in its own
+// tables. Owners are asked in turn when an account is exported or deleted.
+type AccountDataOwner interface {
+	// ExportAccountData adds the user's records to the export.
+	ExportAccountData(userID int, export *model.AccountExport) error
+	// CheckAccountDeletion returns why the account cannot be deleted yet,
+	// or nil. It runs in the deletion's transaction with the user row
+	// locked.
+	CheckAccountDeletion(tx *sql.Tx, userID int) error
+	// DeleteAccountData removes or anonymizes the user's records as part of
+	// the deletion's transaction.
+	DeleteAccountData(tx *sql.Tx, userID int) error
+}
+
+// AddAccountDataOwner registers services whose records belong to an
+// account. Owners must be registered before the service handles requests.
+func (s *Service) AddAccountDataOwner(owners ...AccountDataOwner) {
+	s.accountDataOwners = append(s.accountDataOwners, owners...)
+}
+
+// ExportAccountData collects the profile and every record tied to the user
+// for a personal data export.
+func (s *Service) ExportAccountData(userID int) (*model.AccountExport, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	export := &model.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    user,
+	}
+
+	if export.Sessions, err = s.sessionRepository.GetSessionsByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.APIKeys, err = s.apiKeyRepository.GetAPIKeysByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = s.identityRepository.GetIdentitiesByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.Lockouts, err = s.lockoutRepository.GetLockoutsByUserID(userID); err != nil {
+		return nil, err
+	}
+	if export.AuthEvents, err = s.authEventRepository.GetEventsByUserID(userID); err != nil {
+		return nil, err
+	}
+
+	for _, owner := range s.accountDataOwners {
+		if err := owner.ExportAccountData(userID, export); err != nil {
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// DeleteAccount lets users delete their own account after confirming the
+// password and, when enabled, a two-factor code.
+func (s *Service) DeleteAccount(userID int, req model.DeleteAccountRequest) error {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	if user.IsTwoFactorEnabled() {
+		valid, err := s.verifyTwoFactorCode(user, req.Code)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return errors.New("invalid two-factor code")
+		}
+	}
+
+	return s.deleteAccount(user)
+}
+
+// DeleteUserAccount is used by admins to carry out a deletion request on a
+// user's behalf.
+func (s *Service) DeleteUserAccount(userID, adminID int) error {
+	if userID == adminID {
+		return errors.New("cannot delete your own account here")
+	}
+
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.deleteAccount(user)
+}
+
+// deleteAccount anonymizes the user row rather than removing it, so loans,
+// fines and other records that must be kept still reference a valid user.
+func (s *Service) deleteAccount(user *model.User) error {
+	if user.IsDeleted() {
+		return errors.New("account is already deleted")
+	}
+
+	if user.Role == model.RoleAdmin && user.IsActive() {
+		admins, err := s.userRepository.CountActiveUsersByRole(model.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return errors.New("cannot delete the last admin")
+		}
+	}
+
+	suffix, err := generateRandomToken(4)
+	if err != nil {
+		return err
+	}
+	randomPassword, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// Everything tied to the account is erased together or not at all
+	tx, err := s.userRepository.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The lock holds off checkouts until the account is gone, so the checks
+	// below stay true until the commit
+	username, err := s.userRepository.LockUser(tx, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.New("account is already deleted")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Borrowed copies and unpaid fines have to be settled first
+	for _, owner := range s.accountDataOwners {
+		if err := owner.CheckAccountDeletion(tx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	placeholder := fmt.Sprintf("deleted-%d-%s", user.ID, suffix)
+	if err := s.userRepository.AnonymizeUser(tx, user.ID, placeholder, placeholder+"@deleted.invalid", string(hashedPassword)); err != nil {
+		return err
+	}
+
+	if err := s.deleteCredentials(tx, user.ID, username); err != nil {
+		return err
+	}
+	for _, owner := range s.accountDataOwners {
+		if err := owner.DeleteAccountData(tx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Recorded without client details, which were just scrubbed from the log
+	event := newAuthEvent(model.AuthEventAccountDeleted, model.ClientInfo{}, nil)
+	event.UserID = &user.ID
+	s.RecordAuthEvent(event)
+	return nil
+}
+
+// deleteCredentials removes the tokens, sessions, API keys and identities of
+// a deleted account. Audit and lockout entries are kept without the
+// username, IP address and user agent.
+func (s *Service) deleteCredentials(tx *sql.Tx, userID int, username string) error {
+	if err := s.tokenRepository.DeleteUserTokens(tx, userID); err != nil {
+		return err
+	}
+	if err := s.sessionRepository.DeleteUserSessions(tx, userID); err != nil {
+		return err
+	}
+	if err := s.apiKeyRepository.DeleteUserAPIKeys(tx, userID); err != nil {
+		return err
+	}
+	if err := s.identityRepository.DeleteUserIdentities(tx, userID); err != nil {
+		return err
+	}
+	if err := s.lockoutRepository.AnonymizeUserLockouts(tx, userID, username); err != nil {
+		return err
+	}
+	return s.authEventRepository.AnonymizeUserEvents(tx, userID, username)
+}
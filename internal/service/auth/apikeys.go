@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+const (
+	apiKeyPrefix        = "elib_"
+	apiKeyTouchInterval = time.Minute
+)
+
+// CreateAPIKey issues a new key for the user. The plaintext key is returned
+// only here; afterwards just its hash and prefix are stored.
+func (s *Service) CreateAPIKey(userID int, req model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	for _, scope := range req.Scopes {
+		if !roleAllowed(user.Role, model.ScopeRoles[scope]) {
+			return nil, errors.New("scope " + scope + " is not allowed for your role")
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	secret, err := generateRandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := apiKeyPrefix + secret
+
+	key := &model.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		KeyPrefix: plaintext[:len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(plaintext),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	err = s.apiKeyRepository.CreateAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CreateAPIKeyResponse{
+		APIKey: *key,
+		Key:    plaintext,
+	}, nil
+}
+
+func (s *Service) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	return s.apiKeyRepository.GetAPIKeysByUserID(userID)
+}
+
+func (s *Service) RevokeAPIKey(userID, id int) error {
+	revoked, err := s.apiKeyRepository.RevokeAPIKey(id, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("API key not found")
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves the owner of an X-API-Key header. The owner's
+// current role and verification state apply, narrowed to the key's scopes.
+func (s *Service) AuthenticateAPIKey(key string) (*model.Principal, error) {
+	apiKey, err := s.apiKeyRepository.GetAPIKeyByHash(hashToken(key))
+	if err != nil {
+		return nil, errors.New("invalid API key")
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil {
+		return nil, errors.New("API key has been revoked")
+	}
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return nil, errors.New("API key has expired")
+	}
+
+	user, err := s.userRepository.GetUserByID(apiKey.UserID)
+	if err != nil {
+		return nil, errors.New("invalid API key")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+
+	err = s.apiKeyRepository.TouchAPIKey(apiKey.ID, now, now.Add(-apiKeyTouchInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Principal{
+		UserID:                 user.ID,
+		Username:               user.Username,
+		Role:                   user.Role,
+		EmailVerified:          user.IsEmailVerified(),
+		TwoFactorSetupRequired: s.twoFactorSetupRequired(user),
+		APIKeyID:               apiKey.ID,
+		Scopes:                 apiKey.Scopes,
+	}, nil
+}
+
+func roleAllowed(role string, allowed []string) bool {
+	for _, r := range allowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
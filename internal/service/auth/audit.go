@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"log"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// Column limits of auth_events; longer values are cut rather than failing
+// the insert.
+const (
+	maxAuditUsernameLength  = 255
+	maxAuditReasonLength    = 255
+	maxAuditUserAgentLength = 255
+)
+
+// RecordAuthEvent writes an entry to the authentication audit log. Failures
+// are only logged so auditing never breaks the request being audited.
+func (s *Service) RecordAuthEvent(event *model.AuthEvent) {
+	event.Username = truncate(event.Username, maxAuditUsernameLength)
+	event.Reason = truncate(event.Reason, maxAuditReasonLength)
+	event.UserAgent = truncate(event.UserAgent, maxAuditUserAgentLength)
+
+	if err := s.authEventRepository.CreateEvent(event); err != nil {
+		log.Printf("Failed to record %s auth event: %v", event.Type, err)
+	}
+}
+
+// recordAuthEvent audits the outcome of an auth operation. user is the account
+// involved when known; otherwise username is what the client sent.
+func (s *Service) recordAuthEvent(eventType string, user *model.User, username string, client model.ClientInfo, err error) {
+	event := newAuthEvent(eventType, client, err)
+	event.Username = username
+	if user != nil {
+		event.UserID = &user.ID
+		event.Username = user.Username
+	}
+
+	s.RecordAuthEvent(event)
+}
+
+// newAuthEvent describes an operation that ended with err, which is nil on
+// success.
+func newAuthEvent(eventType string, client model.ClientInfo, err error) *model.AuthEvent {
+	event := &model.AuthEvent{
+		Type:      eventType,
+		Outcome:   model.AuthOutcomeSuccess,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	}
+	if err != nil {
+		event.Outcome = model.AuthOutcomeFailure
+		event.Reason = err.Error()
+	}
+	return event
+}
+
+// truncate cuts value to length characters, matching how VARCHAR counts.
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
@@ -0,0 +1,25 @@
+package auth
+
+import "time"
+
+// PurgeExpiredRecords deletes expired refresh tokens, revoked access token
+// entries, password resets and OIDC login states, and returns how many rows
+// were removed. OIDC states are not counted.
+func (s *Service) PurgeExpiredRecords() (int, error) {
+	now := time.Now()
+	purged := 0
+
+	for _, deleteExpired := range []func(time.Time) (int, error){
+		s.tokenRepository.DeleteExpiredRefreshTokens,
+		s.tokenRepository.DeleteExpiredRevokedTokens,
+		s.tokenRepository.DeleteExpiredPasswordResets,
+	} {
+		deleted, err := deleteExpired(now)
+		if err != nil {
+			return purged, err
+		}
+		purged += deleted
+	}
+
+	return purged, s.identityRepository.DeleteExpiredOIDCStates(now)
+}
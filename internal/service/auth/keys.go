@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one entry of the key set. Only the active key has a private
+// half; retired keys stay around so tokens they signed keep verifying until
+// they expire.
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+type keySet struct {
+	active       *signingKey
+	verification map[string]*signingKey
+}
+
+// LoadSigningKeys reads the access token keys configured under jwt. With the
+// default HS256 algorithm no files are needed and SecretKey is used.
+func (s *Service) LoadSigningKeys(cfg configs.JWT) error {
+	keys, err := loadKeySet(cfg)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	return nil
+}
+
+// keySet returns the loaded keys, falling back to the shared secret when
+// LoadSigningKeys was never called.
+func (s *Service) keySet() *keySet {
+	if s.keys != nil {
+		return s.keys
+	}
+	return hmacKeySet(configs.Get().JWT.SecretKey)
+}
+
+// JWKS lists the public verification keys so other services can check access
+// tokens without the signing secret. It is empty in HS256 mode.
+func (s *Service) JWKS() *model.JWKSet {
+	set := &model.JWKSet{Keys: []model.JWK{}}
+	for _, key := range s.keySet().verification {
+		if jwk, ok := key.jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func (s *Service) signAccessToken(claims jwt.MapClaims) (string, error) {
+	key := s.keySet().active
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+	return token.SignedString(key.private)
+}
+
+func (s *Service) parseAccessToken(tokenString string) (*jwt.Token, error) {
+	keys := s.keySet()
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.verification[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
+}
+
+func hmacKeySet(secret string) *keySet {
+	key := &signingKey{
+		method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+	return &keySet{
+		active:       key,
+		verification: map[string]*signingKey{"": key},
+	}
+}
+
+func loadKeySet(cfg configs.JWT) (*keySet, error) {
+	switch cfg.Algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		return hmacKeySet(cfg.SecretKey), nil
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+
+	if cfg.PrivateKeyPath == "" {
+		return nil, errors.New("jwt.privateKeyPath is required for " + cfg.Algorithm)
+	}
+
+	active, err := loadPrivateKey(cfg.PrivateKeyPath, cfg.Algorithm, cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &keySet{
+		active:       active,
+		verification: map[string]*signingKey{active.id: active},
+	}
+
+	for _, file := range cfg.VerificationKeys {
+		key, err := loadPublicKey(file.PublicKeyPath, file.KeyID)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := keys.verification[key.id]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", key.id)
+		}
+		keys.verification[key.id] = key
+	}
+
+	return keys, nil
+}
+
+func loadPrivateKey(path, algorithm, keyID string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt private key: %v", err)
+	}
+
+	key := &signingKey{id: keyID}
+	if algorithm == jwt.SigningMethodRS256.Alg() {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt private key: %v", err)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodRS256, private, &private.PublicKey
+	} else {
+		private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt private key: %v", err)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, private, private.(ed25519.PrivateKey).Public()
+	}
+
+	if key.id == "" {
+		key.id, err = keyThumbprint(key.public)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+func loadPublicKey(path, keyID string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt public key: %v", err)
+	}
+
+	key := &signingKey{id: keyID}
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		key.method, key.public = jwt.SigningMethodRS256, public
+	} else if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		key.method, key.public = jwt.SigningMethodEdDSA, public
+	} else {
+		return nil, fmt.Errorf("failed to parse jwt public key %s", path)
+	}
+
+	if key.id == "" {
+		key.id, err = keyThumbprint(key.public)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// keyThumbprint derives a stable key ID from the public key so rotating
+// files without configuring keyId still yields distinct IDs.
+func keyThumbprint(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
+
+func (k *signingKey) jwk() (model.JWK, bool) {
+	jwk := model.JWK{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return model.JWK{}, false
+	}
+	return jwk, true
+}
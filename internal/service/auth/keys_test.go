@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKeyPair writes a fresh key pair for algorithm as PEM files and
+// returns their paths.
+func writeKeyPair(t *testing.T, algorithm string) (privatePath, publicPath string) {
+	t.Helper()
+
+	var private crypto.PrivateKey
+	var public crypto.PublicKey
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, &key.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, pub
+	default:
+		t.Fatalf("no key pair for %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath = filepath.Join(dir, "private.pem")
+	publicPath = filepath.Join(dir, "public.pem")
+	writePEM(t, privatePath, "PRIVATE KEY", privateDER)
+	writePEM(t, publicPath, "PUBLIC KEY", publicDER)
+	return privatePath, publicPath
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newKeyService(t *testing.T, cfg configs.JWT) *Service {
+	t.Helper()
+	s := &Service{}
+	if err := s.LoadSigningKeys(cfg); err != nil {
+		t.Fatalf("LoadSigningKeys: %v", err)
+	}
+	return s
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": 7,
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+// publicKeyFromJWK rebuilds the public key a JWKS consumer would use.
+func publicKeyFromJWK(t *testing.T, jwk model.JWK) crypto.PublicKey {
+	t.Helper()
+
+	decode := func(value string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			t.Fatalf("invalid base64url %q: %v", value, err)
+		}
+		return b
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(decode(jwk.N)),
+			E: int(new(big.Int).SetBytes(decode(jwk.E)).Int64()),
+		}
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			t.Fatalf("unexpected curve %q", jwk.Crv)
+		}
+		return ed25519.PublicKey(decode(jwk.X))
+	}
+	t.Fatalf("unexpected key type %q", jwk.Kty)
+	return nil
+}
+
+func TestHS256RoundTrip(t *testing.T) {
+	s := newKeyService(t, configs.JWT{SecretKey: "test-secret"})
+
+	tokenString, err := s.signAccessToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		t.Fatalf("parseAccessToken: %v", err)
+	}
+	if token.Method.Alg() != "HS256" {
+		t.Errorf("alg = %s, want HS256", token.Method.Alg())
+	}
+	if _, ok := token.Header["kid"]; ok {
+		t.Error("HS256 token carries a kid")
+	}
+
+	if keys := s.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("JWKS lists %d keys in HS256 mode, want none", len(keys))
+	}
+
+	other := newKeyService(t, configs.JWT{SecretKey: "other-secret"})
+	if _, err := other.parseAccessToken(tokenString); err == nil {
+		t.Error("token verified with a different secret")
+	}
+}
+
+func TestAsymmetricKidAndJWKSRoundTrip(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		kty       string
+	}{
+		{jwt.SigningMethodRS256.Alg(), "RSA"},
+		{jwt.SigningMethodEdDSA.Alg(), "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			privatePath, _ := writeKeyPair(t, tt.algorithm)
+			s := newKeyService(t, configs.JWT{Algorithm: tt.algorithm, PrivateKeyPath: privatePath})
+
+			tokenString, err := s.signAccessToken(testClaims())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Without a configured key ID the kid is the key's thumbprint
+			wantKid, err := keyThumbprint(s.keys.active.public)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := s.parseAccessToken(tokenString)
+			if err != nil {
+				t.Fatalf("parseAccessToken: %v", err)
+			}
+			if kid := token.Header["kid"]; kid != wantKid {
+				t.Errorf("kid = %v, want %s", kid, wantKid)
+			}
+			if token.Method.Alg() != tt.algorithm {
+				t.Errorf("alg = %s, want %s", token.Method.Alg(), tt.algorithm)
+			}
+
+			keys := s.JWKS().Keys
+			if len(keys) != 1 {
+				t.Fatalf("JWKS lists %d keys, want 1", len(keys))
+			}
+			jwk := keys[0]
+			if jwk.Kid != wantKid || jwk.Kty != tt.kty || jwk.Alg != tt.algorithm || jwk.Use != "sig" {
+				t.Errorf("JWK = %+v", jwk)
+			}
+
+			// A consumer verifying with only the JWKS accepts the token
+			public := publicKeyFromJWK(t, jwk)
+			_, err = jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return public, nil },
+				jwt.WithValidMethods([]string{tt.algorithm}))
+			if err != nil {
+				t.Errorf("token does not verify with the JWKS key: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfiguredKeyID(t *testing.T) {
+	privatePath, _ := writeKeyPair(t, jwt.SigningMethodEdDSA.Alg())
+	s := newKeyService(t, configs.JWT{Algorithm: "EdDSA", KeyID: "2026-01", PrivateKeyPath: privatePath})
+
+	tokenString, err := s.signAccessToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Header["kid"] != "2026-01" {
+		t.Errorf("kid = %v, want 2026-01", token.Header["kid"])
+	}
+	if keys := s.JWKS().Keys; len(keys) != 1 || keys[0].Kid != "2026-01" {
+		t.Errorf("JWKS = %+v", keys)
+	}
+}
+
+// After a rotation the retired key only verifies, so tokens it signed stay
+// valid until they expire.
+func TestRotation(t *testing.T) {
+	oldPrivate, oldPublic := writeKeyPair(t, jwt.SigningMethodRS256.Alg())
+	newPrivate, _ := writeKeyPair(t, jwt.SigningMethodEdDSA.Alg())
+
+	before := newKeyService(t, configs.JWT{Algorithm: "RS256", KeyID: "old", PrivateKeyPath: oldPrivate})
+	oldToken, err := before.signAccessToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := newKeyService(t, configs.JWT{
+		Algorithm:        "EdDSA",
+		KeyID:            "new",
+		PrivateKeyPath:   newPrivate,
+		VerificationKeys: []configs.JWTKeyFile{{KeyID: "old", PublicKeyPath: oldPublic}},
+	})
+	if _, err := after.parseAccessToken(oldToken); err != nil {
+		t.Errorf("token of the retired key no longer verifies: %v", err)
+	}
+
+	newToken, err := after.signAccessToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := after.parseAccessToken(newToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Header["kid"] != "new" || token.Method.Alg() != "EdDSA" {
+		t.Errorf("new token has kid %v and alg %s", token.Header["kid"], token.Method.Alg())
+	}
+
+	keys := after.JWKS().Keys
+	if len(keys) != 2 || keys[0].Kid != "new" || keys[1].Kid != "old" {
+		t.Errorf("JWKS = %+v, want new and old", keys)
+	}
+
+	// The old service does not know the new key
+	if _, err := before.parseAccessToken(newToken); err == nil {
+		t.Error("token verified with an unknown kid")
+	}
+}
+
+func TestParseRejectsAlgorithmMismatch(t *testing.T) {
+	privatePath, _ := writeKeyPair(t, jwt.SigningMethodRS256.Alg())
+	s := newKeyService(t, configs.JWT{Algorithm: "RS256", KeyID: "k1", PrivateKeyPath: privatePath})
+
+	// HS256 signed with the public key bytes, claiming the RSA key's kid
+	der, err := x509.MarshalPKIXPublicKey(s.keys.active.public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = "k1"
+	tokenString, err := forged.SignedString(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.parseAccessToken(tokenString); err == nil {
+		t.Error("token with a mismatched algorithm was accepted")
+	}
+}
+
+func TestLoadSigningKeysErrors(t *testing.T) {
+	rsaPrivate, rsaPublic := writeKeyPair(t, jwt.SigningMethodRS256.Alg())
+
+	tests := []struct {
+		name string
+		cfg  configs.JWT
+	}{
+		{"unsupported algorithm", configs.JWT{Algorithm: "ES256"}},
+		{"missing private key path", configs.JWT{Algorithm: "RS256"}},
+		{"missing private key file", configs.JWT{Algorithm: "RS256", PrivateKeyPath: filepath.Join(t.TempDir(), "none.pem")}},
+		{"wrong key type", configs.JWT{Algorithm: "EdDSA", PrivateKeyPath: rsaPrivate}},
+		{"duplicate key ID", configs.JWT{
+			Algorithm:        "RS256",
+			KeyID:            "k1",
+			PrivateKeyPath:   rsaPrivate,
+			VerificationKeys: []configs.JWTKeyFile{{KeyID: "k1", PublicKeyPath: rsaPublic}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (&Service{}).LoadSigningKeys(tt.cfg); err == nil {
+				t.Error("LoadSigningKeys succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/ferdy-adr/elibrary-backend/pkg/oidc"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultOIDCStateTTL = 10 * time.Minute
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *Service) GetOIDCProviders() []model.OIDCProviderInfo {
+	providers := []model.OIDCProviderInfo{}
+	for _, cfg := range configs.Get().OIDC.Providers {
+		displayName := cfg.DisplayName
+		if displayName == "" {
+			displayName = cfg.Name
+		}
+		providers = append(providers, model.OIDCProviderInfo{Name: cfg.Name, DisplayName: displayName})
+	}
+	return providers
+}
+
+// StartOIDCLogin creates the state, nonce and PKCE verifier for a new login
+// and returns the provider URL the browser should be sent to.
+func (s *Service) StartOIDCLogin(providerName string) (*model.OIDCAuthorization, error) {
+	provider, err := s.oidcProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := provider.AuthCodeURL(state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.identityRepository.DeleteExpiredOIDCStates(now); err != nil {
+		log.Printf("Failed to delete expired OIDC states: %v", err)
+	}
+
+	err = s.identityRepository.CreateOIDCState(&model.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(oidcStateTTL()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OIDCAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+	}, nil
+}
+
+// CompleteOIDCLogin handles the provider callback. The identity is matched by
+// provider and subject; unknown identities get a new member account unless
+// the provider is allowed to link by email.
+func (s *Service) CompleteOIDCLogin(providerName string, req model.OIDCCallbackRequest, client model.ClientInfo) (response *model.LoginResponse, err error) {
+	var user *model.User
+	defer func() {
+		eventType := model.AuthEventOIDCLogin
+		if response != nil && response.TwoFactorRequired {
+			eventType = model.AuthEventTwoFactorChallenge
+		}
+		s.recordAuthEvent(eventType, user, "", client, err)
+	}()
+
+	provider, err := s.oidcProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.identityRepository.ConsumeOIDCState(hashToken(req.State))
+	if err != nil || state.Provider != providerName || time.Now().After(state.ExpiresAt) {
+		return nil, errors.New("invalid or expired login state")
+	}
+
+	rawIDToken, err := provider.Exchange(req.Code, state.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC code exchange with %s failed: %v", providerName, err)
+		return nil, errors.New("identity provider rejected the login")
+	}
+
+	claims, err := provider.VerifyIDToken(rawIDToken, state.Nonce)
+	if err != nil {
+		log.Printf("OIDC id_token from %s rejected: %v", providerName, err)
+		return nil, errors.New("identity provider rejected the login")
+	}
+
+	user, err = s.resolveOIDCUser(providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+
+	if user.IsTwoFactorEnabled() {
+		challengeToken, err := signPurposeToken(twoFactorChallengePurpose, user.ID, twoFactorChallengeTTL(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	return s.completeLogin(user, client)
+}
+
+func (s *Service) GetIdentities(userID int) ([]model.UserIdentity, error) {
+	return s.identityRepository.GetIdentitiesByUserID(userID)
+}
+
+func (s *Service) resolveOIDCUser(providerName string, claims *oidc.IDTokenClaims) (*model.User, error) {
+	now := time.Now()
+	var email *string
+	if claims.Email != "" {
+		email = &claims.Email
+	}
+
+	identity, err := s.identityRepository.GetIdentity(providerName, claims.Subject)
+	if err == nil {
+		if err := s.identityRepository.TouchIdentity(identity.ID, email, now); err != nil {
+			return nil, err
+		}
+		return s.userRepository.GetUserByID(identity.UserID)
+	}
+
+	if claims.Email == "" {
+		return nil, errors.New("identity provider did not return an email address")
+	}
+
+	user, _ := s.userRepository.GetUserByEmail(claims.Email)
+	if user != nil {
+		if !oidcProviderConfig(providerName).LinkByEmail || !claims.EmailVerified {
+			return nil, errors.New("an account with this email already exists")
+		}
+	} else {
+		user, err = s.createOIDCUser(claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identityRepository.CreateIdentity(&model.UserIdentity{
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createOIDCUser registers a member account for a first-time external login.
+// It gets an unusable random password; a local one can be set through the
+// forgot password flow.
+func (s *Service) createOIDCUser(claims *oidc.IDTokenClaims) (*model.User, error) {
+	username, err := s.availableUsername(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := claims.Name
+	if fullName == "" {
+		fullName = username
+	}
+
+	user := &model.User{
+		Username: username,
+		Email:    claims.Email,
+		Password: string(hashedPassword),
+		FullName: fullName,
+		Role:     model.RoleMember,
+	}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	err = s.userRepository.CreateUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		if err := s.SendVerificationEmail(user); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}
+
+// availableUsername derives a username from the preferred_username or email
+// claim, adding a numeric suffix when it is already taken.
+func (s *Service) availableUsername(claims *oidc.IDTokenClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	base = strings.Trim(usernameInvalidChars.ReplaceAllString(base, ""), ".-_")
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 50 {
+		base = base[:50]
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		existing, _ := s.userRepository.GetUserByUsername(candidate)
+		if existing == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("could not find an available username")
+}
+
+// oidcProvider returns the client for a configured provider, reusing it
+// between requests so discovery results and keys stay cached.
+func (s *Service) oidcProvider(name string) (*oidc.Provider, error) {
+	cfg := oidcProviderConfig(name)
+	if cfg == nil {
+		return nil, errors.New("unknown identity provider")
+	}
+
+	s.oidcMu.Lock()
+	defer s.oidcMu.Unlock()
+
+	if s.oidcProviders == nil {
+		s.oidcProviders = map[string]*oidc.Provider{}
+	}
+	provider, ok := s.oidcProviders[name]
+	if !ok {
+		provider = oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.Issuer,
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+		})
+		s.oidcProviders[name] = provider
+	}
+	return provider, nil
+}
+
+func oidcProviderConfig(name string) *configs.OIDCProvider {
+	providers := configs.Get().OIDC.Providers
+	for i := range providers {
+		if providers[i].Name == name {
+			return &providers[i]
+		}
+	}
+	return nil
+}
+
+func oidcStateTTL() time.Duration {
+	if ttl := configs.Get().OIDC.StateTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultOIDCStateTTL
+}
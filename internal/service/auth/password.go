@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/ferdy-adr/elibrary-backend/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultPasswordResetTTL = time.Hour
+
+// ForgotPassword emails a single-use reset link to the account owning the
+// given email. It succeeds even for unknown emails so callers cannot probe
+// which addresses are registered.
+func (s *Service) ForgotPassword(req model.ForgotPasswordRequest, client model.ClientInfo) error {
+	user, err := s.userRepository.GetUserByEmail(req.Email)
+	if err != nil {
+		s.recordAuthEvent(model.AuthEventPasswordResetRequested, nil, req.Email, client, errors.New("email not registered"))
+		return nil
+	}
+	s.recordAuthEvent(model.AuthEventPasswordResetRequested, user, "", client, nil)
+
+	return s.sendPasswordReset(
+		user,
+		"We received a request to reset your password.",
+		"If you did not request this, you can ignore this email.",
+	)
+}
+
+// ForcePasswordReset is used by admins. Password logins are refused and all
+// sessions end until the user picks a new password through the emailed link.
+func (s *Service) ForcePasswordReset(userID int) error {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	err = s.userRepository.RequirePasswordReset(userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.sessionRepository.RevokeUserSessions(userID, 0)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPasswordReset(
+		user,
+		"An administrator requires you to choose a new password before you can sign in again.",
+		"Until then, signing in with your current password is not possible.",
+	)
+}
+
+func (s *Service) ResetPassword(req model.ResetPasswordRequest, client model.ClientInfo) (err error) {
+	var user *model.User
+	defer func() { s.recordAuthEvent(model.AuthEventPasswordReset, user, "", client, err) }()
+
+	reset, err := s.tokenRepository.GetPasswordResetByHash(hashToken(req.Token))
+	if err != nil || reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return errors.New("invalid or expired reset token")
+	}
+
+	user, err = s.userRepository.GetUserByID(reset.UserID)
+	if err != nil {
+		return errors.New("invalid or expired reset token")
+	}
+	// Checked before the token is used up so a rejected password can be retried
+	if err := s.checkPassword("password", req.Password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// The token is only used up together with the password change, so a
+	// failed update leaves it valid for another try
+	tx, err := s.userRepository.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	used, err := s.tokenRepository.MarkPasswordResetUsed(tx, reset.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid or expired reset token")
+	}
+
+	err = s.userRepository.UpdateUserPasswordTx(tx, reset.UserID, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password must not stay logged in
+	return s.sessionRepository.RevokeUserSessions(reset.UserID, 0)
+}
+
+func passwordResetTTL() time.Duration {
+	if ttl := configs.Get().Auth.PasswordResetTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultPasswordResetTTL
+}
+
+// sendPasswordReset emails a fresh single-use reset link. Delivery failures
+// are only logged, the link can be requested again.
+func (s *Service) sendPasswordReset(user *model.User, intro, note string) error {
+	// Only the most recent link should work
+	if err := s.tokenRepository.InvalidateUserPasswordResets(user.ID); err != nil {
+		return err
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	ttl := passwordResetTTL()
+	err = s.tokenRepository.CreatePasswordReset(&model.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	resetLink := configs.Get().Auth.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your eLibrary password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s Use the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can only be used once. %s\n",
+			user.FullName, intro, resetLink, ttl, note,
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
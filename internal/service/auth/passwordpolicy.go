@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"log"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
+	"github.com/ferdy-adr/elibrary-backend/pkg/passwordpolicy"
+)
+
+const defaultPasswordMinLength = 8
+
+// ValidationError is returned when request fields are rejected. Conflict is
+// set when a field clashes with existing data, such as a taken username.
+type ValidationError struct {
+	Conflict bool
+	Fields   []model.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// duplicateConflict turns a unique key violation on the users table into
+// the same conflict a lookup would have reported.
+func duplicateConflict(err error) error {
+	if field := userRepo.DuplicateField(err); field != "" {
+		return &ValidationError{
+			Conflict: true,
+			Fields:   []model.FieldError{{Field: field, Message: "already exists"}},
+		}
+	}
+	return err
+}
+
+// checkPassword applies the configured password policy. personal holds the
+// username and email the password must not contain.
+func (s *Service) checkPassword(field, password string, personal ...string) error {
+	violations := s.passwordPolicy().Validate(password, personal...)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	validationErr := &ValidationError{}
+	for _, violation := range violations {
+		validationErr.Fields = append(validationErr.Fields, model.FieldError{Field: field, Message: violation})
+	}
+	return validationErr
+}
+
+// passwordPolicy builds the policy from config on first use so the breached
+// list is only read once.
+func (s *Service) passwordPolicy() *passwordpolicy.Policy {
+	s.policyOnce.Do(func() {
+		cfg := configs.Get().Auth.PasswordPolicy
+		minLength := cfg.MinLength
+		if minLength <= 0 {
+			minLength = defaultPasswordMinLength
+		}
+
+		s.policy = passwordpolicy.New(passwordpolicy.Policy{
+			MinLength:        minLength,
+			RequireUppercase: cfg.RequireUppercase,
+			RequireLowercase: cfg.RequireLowercase,
+			RequireDigit:     cfg.RequireDigit,
+			RequireSymbol:    cfg.RequireSymbol,
+			CheckBreached:    !cfg.DisableBreachedCheck,
+		})
+		if cfg.BreachedListPath != "" {
+			if err := s.policy.LoadList(cfg.BreachedListPath); err != nil {
+				log.Printf("Failed to load breached password list: %v", err)
+			}
+		}
+	})
+	return s.policy
+}
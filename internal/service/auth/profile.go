@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *Service) GetProfile(userID int) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (s *Service) UpdateProfile(userID int, req model.UpdateProfileRequest) (*model.User, error) {
+	existingUser, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	update := &model.User{
+		FullName: strings.TrimSpace(req.FullName),
+	}
+
+	// Only touch the email when it actually changes, since that resets verification
+	if req.Email != "" && !strings.EqualFold(req.Email, existingUser.Email) {
+		owner, _ := s.userRepository.GetUserByEmail(req.Email)
+		if owner != nil && owner.ID != userID {
+			return nil, &ValidationError{
+				Conflict: true,
+				Fields:   []model.FieldError{{Field: "email", Message: "already exists"}},
+			}
+		}
+		update.Email = req.Email
+	}
+
+	if update.FullName == "" && update.Email == "" {
+		return existingUser, nil
+	}
+
+	err = s.userRepository.UpdateUserProfile(userID, update)
+	if err != nil {
+		return nil, duplicateConflict(err)
+	}
+
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Email != "" {
+		if err := s.SendVerificationEmail(user); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+// All other sessions are revoked so other devices are logged out, and a fresh
+// token pair is returned for the caller's session.
+func (s *Service) ChangePassword(userID, sessionID int, req model.ChangePasswordRequest, client model.ClientInfo) (response *model.TokenResponse, err error) {
+	var user *model.User
+	defer func() { s.recordAuthEvent(model.AuthEventPasswordChanged, user, "", client, err) }()
+
+	user, err = s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword))
+	if err != nil {
+		return nil, errors.New("current password is incorrect")
+	}
+
+	if err := s.checkPassword("new_password", req.NewPassword, user.Username, user.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepository.UpdateUserPassword(userID, string(hashedPassword))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepository.RevokeUserSessions(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, sessionID)
+}
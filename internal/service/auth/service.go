@@ -2,30 +2,88 @@ package auth
 
 import (
 	"errors"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/ferdy-adr/elibrary-backend/internal/configs"
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	apiKeyRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/apikeys"
+	authEventRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/authevents"
+	identityRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/identities"
+	lockoutRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/lockouts"
+	sessionRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/sessions"
+	settingRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/settings"
+	tokenRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/tokens"
 	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/ferdy-adr/elibrary-backend/pkg/mailer"
+	"github.com/ferdy-adr/elibrary-backend/pkg/oidc"
+	"github.com/ferdy-adr/elibrary-backend/pkg/passwordpolicy"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Service struct {
-	userRepository *userRepo.Repository
+	userRepository      *userRepo.Repository
+	tokenRepository     *tokenRepo.Repository
+	lockoutRepository   *lockoutRepo.Repository
+	settingRepository   *settingRepo.Repository
+	apiKeyRepository    *apiKeyRepo.Repository
+	identityRepository  *identityRepo.Repository
+	sessionRepository   *sessionRepo.Repository
+	authEventRepository *authEventRepo.Repository
+	mailer              mailer.Mailer
+	keys                *keySet
+
+	accountDataOwners []AccountDataOwner
+
+	oidcMu        sync.Mutex
+	oidcProviders map[string]*oidc.Provider
+
+	policyOnce sync.Once
+	policy     *passwordpolicy.Policy
 }
 
-func NewService(userRepository *userRepo.Repository) *Service {
+func NewService(
+	userRepository *userRepo.Repository,
+	tokenRepository *tokenRepo.Repository,
+	lockoutRepository *lockoutRepo.Repository,
+	settingRepository *settingRepo.Repository,
+	apiKeyRepository *apiKeyRepo.Repository,
+	identityRepository *identityRepo.Repository,
+	sessionRepository *sessionRepo.Repository,
+	authEventRepository *authEventRepo.Repository,
+	mailer mailer.Mailer,
+) *Service {
 	return &Service{
-		userRepository: userRepository,
+		userRepository:      userRepository,
+		tokenRepository:     tokenRepository,
+		lockoutRepository:   lockoutRepository,
+		settingRepository:   settingRepository,
+		apiKeyRepository:    apiKeyRepository,
+		identityRepository:  identityRepository,
+		sessionRepository:   sessionRepository,
+		authEventRepository: authEventRepository,
+		mailer:              mailer,
 	}
 }
 
-func (s *Service) Register(req model.RegisterRequest) (*model.User, error) {
-	// Check if user already exists
-	existingUser, _ := s.userRepository.GetUserByUsername(req.Username)
-	if existingUser != nil {
-		return nil, errors.New("username already exists")
+func (s *Service) Register(req model.RegisterRequest, client model.ClientInfo) (user *model.User, err error) {
+	defer func() { s.recordAuthEvent(model.AuthEventRegister, user, req.Username, client, err) }()
+
+	if err := s.checkPassword("password", req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
+	// Report every taken field at once so the form can flag them together
+	conflict := &ValidationError{Conflict: true}
+	if existingUser, _ := s.userRepository.GetUserByUsername(req.Username); existingUser != nil {
+		conflict.Fields = append(conflict.Fields, model.FieldError{Field: "username", Message: "already exists"})
+	}
+	if existingUser, _ := s.userRepository.GetUserByEmail(req.Email); existingUser != nil {
+		conflict.Fields = append(conflict.Fields, model.FieldError{Field: "email", Message: "already exists"})
+	}
+	if len(conflict.Fields) > 0 {
+		return nil, conflict
 	}
 
 	// Hash password
@@ -34,53 +92,158 @@ func (s *Service) Register(req model.RegisterRequest) (*model.User, error) {
 		return nil, err
 	}
 
-	user := &model.User{
+	user = &model.User{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: string(hashedPassword),
 		FullName: req.FullName,
+		Role:     model.RoleMember,
 	}
 
 	err = s.userRepository.CreateUser(user)
 	if err != nil {
-		return nil, err
+		return nil, duplicateConflict(err)
+	}
+
+	// The account is usable right away, but stays unverified until the link is opened
+	if err := s.SendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
 	}
 
 	return user, nil
 }
 
-func (s *Service) Login(req model.LoginRequest) (*model.LoginResponse, error) {
+func (s *Service) Login(req model.LoginRequest, client model.ClientInfo) (response *model.LoginResponse, err error) {
+	var user *model.User
+	defer func() {
+		eventType := model.AuthEventLogin
+		if response != nil && response.TwoFactorRequired {
+			eventType = model.AuthEventTwoFactorChallenge
+		}
+		s.recordAuthEvent(eventType, user, req.Username, client, err)
+	}()
+
+	now := time.Now()
+
+	// Refuse early while the username or IP is locked or backing off, and
+	// count the attempt until the password proves it was not a failure
+	attempt, err := s.beginLoginAttempt(req.Username, client.IPAddress, now)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get user by username
-	user, err := s.userRepository.GetUserByUsername(req.Username)
+	user, err = s.userRepository.GetUserByUsername(req.Username)
 	if err != nil {
+		if err := s.recordLoginFailure(attempt, nil, now); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid username or password")
 	}
 
 	// Check password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
+		if err := s.recordLoginFailure(attempt, user, now); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid username or password")
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user)
+	if err := s.recordLoginSuccess(attempt); err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+	if user.PasswordResetRequired {
+		return nil, errors.New("password reset required, check your email for a reset link")
+	}
+
+	// Accounts with two-factor authentication get a challenge instead of tokens
+	if user.IsTwoFactorEnabled() {
+		challengeToken, err := signPurposeToken(twoFactorChallengePurpose, user.ID, twoFactorChallengeTTL(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	return s.completeLogin(user, client)
+}
+
+// completeLogin starts a session and issues the token pair for a fully
+// authenticated user.
+func (s *Service) completeLogin(user *model.User, client model.ClientInfo) (*model.LoginResponse, error) {
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+
+	session, err := s.createSession(user.ID, client, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate access and refresh tokens
+	tokens, err := s.issueTokens(user, session.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &model.LoginResponse{
-		Token: token,
-		User:  *user,
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresAt:              &tokens.ExpiresAt,
+		User:                   user,
+		TwoFactorSetupRequired: s.twoFactorSetupRequired(user),
 	}, nil
 }
 
-func (s *Service) generateToken(user *model.User) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"iat":      time.Now().Unix(),
+// BootstrapAdmin makes sure the admin account configured under `admin` exists
+// and has the admin role. It does nothing when no admin username is configured.
+func (s *Service) BootstrapAdmin(cfg configs.Admin) error {
+	if cfg.Username == "" {
+		return nil
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(configs.Get().JWT.SecretKey))
+	existingUser, _ := s.userRepository.GetUserByUsername(cfg.Username)
+	if existingUser != nil {
+		if existingUser.Role == model.RoleAdmin {
+			return nil
+		}
+		log.Printf("Promoting existing user %s to admin", cfg.Username)
+		return s.userRepository.UpdateUserRole(existingUser.ID, model.RoleAdmin)
+	}
+
+	if cfg.Email == "" || cfg.Password == "" {
+		return errors.New("admin email and password are required to create the admin user")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(cfg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	fullName := cfg.FullName
+	if fullName == "" {
+		fullName = "Administrator"
+	}
+
+	// The configured admin email is trusted, so it starts out verified
+	now := time.Now()
+
+	log.Printf("Creating admin user %s", cfg.Username)
+	return s.userRepository.CreateUser(&model.User{
+		Username:        cfg.Username,
+		Email:           cfg.Email,
+		Password:        string(hashedPassword),
+		FullName:        fullName,
+		Role:            model.RoleAdmin,
+		EmailVerifiedAt: &now,
+	})
 }
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+const (
+	sessionTouchInterval = time.Minute
+	maxUserAgentLength   = 255
+)
+
+// GetSessions lists the user's active sessions and marks the one the request
+// was made with.
+func (s *Service) GetSessions(userID, currentSessionID int) ([]model.Session, error) {
+	sessions, err := s.sessionRepository.GetActiveSessionsByUserID(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *Service) RevokeSession(userID, sessionID int) error {
+	revoked, err := s.sessionRepository.RevokeSession(sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// RevokeAllSessions logs the user out everywhere. With keepSessionID set, that
+// session stays signed in.
+func (s *Service) RevokeAllSessions(userID, keepSessionID int) error {
+	return s.sessionRepository.RevokeUserSessions(userID, keepSessionID)
+}
+
+func (s *Service) createSession(userID int, client model.ClientInfo, now time.Time) (*model.Session, error) {
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	session := &model.Session{
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  client.IPAddress,
+		ExpiresAt:  now.Add(refreshTokenTTL()),
+		LastSeenAt: now,
+	}
+
+	err := s.sessionRepository.CreateSession(session)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"log"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+const (
+	defaultMaxFailedLogins      = 5
+	defaultMaxFailedLoginsPerIP = 20
+	defaultLockoutDuration      = 15 * time.Minute
+	defaultLoginBackoffBase     = time.Second
+)
+
+// LoginThrottleError is returned by Login when an attempt is refused because
+// of earlier failures. Locked distinguishes an account lockout from a
+// temporary backoff or an IP-wide block.
+type LoginThrottleError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottleError) Error() string {
+	if e.Locked {
+		return "account is temporarily locked"
+	}
+	return "too many login attempts"
+}
+
+// loginAttempt is a login attempt already counted as a failure against its
+// username and IP address, with the counts that produced.
+type loginAttempt struct {
+	username      string
+	ipAddress     string
+	failedCount   int
+	ipFailedCount int
+}
+
+// beginLoginAttempt refuses the attempt while the username or IP address is
+// locked, or while the username is still inside its exponential backoff.
+// Otherwise it counts the attempt as a failure against both before the
+// credentials are checked. The check and the increment happen under the same
+// row locks, so concurrent attempts each see the ones before them instead of
+// all passing the check ahead of the first recorded failure.
+func (s *Service) beginLoginAttempt(username, ipAddress string, now time.Time) (*loginAttempt, error) {
+	tx, err := s.lockoutRepository.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	windowStart := now.Add(-lockoutDuration())
+
+	// Lock the username before the IP address in every attempt so two
+	// attempts never wait on each other's rows
+	attempt, err := s.lockoutRepository.LockLoginAttempt(tx, model.LoginKeyUsername, username, now)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		return nil, &LoginThrottleError{Locked: true, RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+	if attempt.LastFailedAt.After(windowStart) {
+		nextAllowed := attempt.LastFailedAt.Add(loginBackoff(attempt.FailedCount))
+		if now.Before(nextAllowed) {
+			return nil, &LoginThrottleError{RetryAfter: nextAllowed.Sub(now)}
+		}
+	}
+
+	ipAttempt, err := s.lockoutRepository.LockLoginAttempt(tx, model.LoginKeyIP, ipAddress, now)
+	if err != nil {
+		return nil, err
+	}
+	if ipAttempt.LockedUntil != nil && now.Before(*ipAttempt.LockedUntil) {
+		return nil, &LoginThrottleError{RetryAfter: ipAttempt.LockedUntil.Sub(now)}
+	}
+
+	// Both keys count every failure, so tripping the username lockout does
+	// not hide attempts from the per-IP limit
+	failedCount, err := s.lockoutRepository.RecordFailedLogin(tx, model.LoginKeyUsername, username, now, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	ipFailedCount, err := s.lockoutRepository.RecordFailedLogin(tx, model.LoginKeyIP, ipAddress, now, windowStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &loginAttempt{
+		username:      username,
+		ipAddress:     ipAddress,
+		failedCount:   failedCount,
+		ipFailedCount: ipFailedCount,
+	}, nil
+}
+
+// recordLoginFailure locks whichever of the username and IP address crossed
+// its threshold with the failure beginLoginAttempt counted. user is nil when
+// the username does not exist.
+func (s *Service) recordLoginFailure(attempt *loginAttempt, user *model.User, now time.Time) error {
+	lockedUntil := now.Add(lockoutDuration())
+
+	var throttleErr error
+	if attempt.ipFailedCount >= maxFailedLoginsPerIP() {
+		if err := s.lockoutRepository.LockLoginKey(model.LoginKeyIP, attempt.ipAddress, lockedUntil); err != nil {
+			return err
+		}
+
+		log.Printf("IP address %s blocked until %s after %d failed logins", attempt.ipAddress, lockedUntil.Format(time.RFC3339), attempt.ipFailedCount)
+		throttleErr = &LoginThrottleError{RetryAfter: lockoutDuration()}
+	}
+
+	if attempt.failedCount >= maxFailedLogins() {
+		if err := s.lockoutRepository.LockLoginKey(model.LoginKeyUsername, attempt.username, lockedUntil); err != nil {
+			return err
+		}
+
+		lockout := &model.AccountLockout{
+			Username:    attempt.username,
+			IPAddress:   attempt.ipAddress,
+			FailedCount: attempt.failedCount,
+			LockedUntil: lockedUntil,
+		}
+		if user != nil {
+			lockout.UserID = &user.ID
+		}
+		if err := s.lockoutRepository.CreateLockout(lockout); err != nil {
+			return err
+		}
+
+		log.Printf("Account %s locked until %s after %d failed logins", attempt.username, lockedUntil.Format(time.RFC3339), attempt.failedCount)
+		throttleErr = &LoginThrottleError{Locked: true, RetryAfter: lockoutDuration()}
+	}
+
+	return throttleErr
+}
+
+// recordLoginSuccess clears the failures of the username and takes back the
+// failure beginLoginAttempt counted against the IP address.
+func (s *Service) recordLoginSuccess(attempt *loginAttempt) error {
+	if err := s.lockoutRepository.ResetLoginAttempts(model.LoginKeyUsername, attempt.username); err != nil {
+		return err
+	}
+	return s.lockoutRepository.ReleaseFailedLogin(model.LoginKeyIP, attempt.ipAddress)
+}
+
+// loginBackoff is the delay required after the given number of consecutive
+// failures: base, 2*base, 4*base, ... capped at the lockout duration.
+func loginBackoff(failedCount int) time.Duration {
+	if failedCount <= 0 {
+		return 0
+	}
+
+	backoff := loginBackoffBase()
+	for i := 1; i < failedCount && backoff < lockoutDuration(); i++ {
+		backoff *= 2
+	}
+	if backoff > lockoutDuration() {
+		backoff = lockoutDuration()
+	}
+	return backoff
+}
+
+func maxFailedLogins() int {
+	if max := configs.Get().Auth.MaxFailedLogins; max > 0 {
+		return max
+	}
+	return defaultMaxFailedLogins
+}
+
+func maxFailedLoginsPerIP() int {
+	if max := configs.Get().Auth.MaxFailedLoginsPerIP; max > 0 {
+		return max
+	}
+	return defaultMaxFailedLoginsPerIP
+}
+
+func lockoutDuration() time.Duration {
+	if d := configs.Get().Auth.LockoutDuration; d > 0 {
+		return d
+	}
+	return defaultLockoutDuration
+}
+
+func loginBackoffBase() time.Duration {
+	if d := configs.Get().Auth.LoginBackoffBase; d > 0 {
+		return d
+	}
+	return defaultLoginBackoffBase
+}
@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Refresh rotates a refresh token. Only failed refreshes are audited; the
+// session's last_seen_at already tracks successful ones.
+func (s *Service) Refresh(req model.RefreshRequest, client model.ClientInfo) (response *model.TokenResponse, err error) {
+	var user *model.User
+	defer func() {
+		if err != nil {
+			s.recordAuthEvent(model.AuthEventTokenRefresh, user, "", client, err)
+		}
+	}()
+
+	stored, err := s.tokenRepository.GetRefreshTokenByHash(hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// A rotated token being presented again means it was leaked or replayed,
+	// so every session of the owner is terminated.
+	if stored.RevokedAt != nil {
+		if stored.ReplacedBy != nil {
+			if err := s.sessionRepository.RevokeUserSessions(stored.UserID, 0); err != nil {
+				return nil, err
+			}
+			event := newAuthEvent(model.AuthEventRefreshTokenReuse, client, errors.New("rotated refresh token reused, all sessions revoked"))
+			event.UserID = &stored.UserID
+			s.RecordAuthEvent(event)
+		}
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token has expired")
+	}
+
+	user, err = s.userRepository.GetUserByID(stored.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+
+	// Tokens issued before sessions existed get a session on first use
+	now := time.Now()
+	var sessionID int
+	if stored.SessionID != nil {
+		sessionID = *stored.SessionID
+	} else {
+		session, err := s.createSession(user.ID, client, now)
+		if err != nil {
+			return nil, err
+		}
+		sessionID = session.ID
+	}
+
+	accessToken, expiresAt, err := s.generateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken := &model.RefreshToken{
+		UserID:    user.ID,
+		SessionID: &sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(refreshTokenTTL()),
+	}
+
+	rotated, err := s.tokenRepository.RotateRefreshToken(stored.ID, newToken)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	err = s.sessionRepository.ExtendSession(sessionID, newToken.ExpiresAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// Logout ends the caller's session, which also invalidates its access and
+// refresh tokens.
+func (s *Service) Logout(userID, sessionID int, tokenID string, tokenExpiresAt time.Time, req model.LogoutRequest, client model.ClientInfo) (err error) {
+	defer func() {
+		event := newAuthEvent(model.AuthEventLogout, client, err)
+		event.UserID = &userID
+		s.RecordAuthEvent(event)
+	}()
+
+	if tokenID != "" {
+		if err := s.tokenRepository.RevokeAccessToken(tokenID, userID, tokenExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	if sessionID != 0 {
+		if _, err := s.sessionRepository.RevokeSession(sessionID, userID); err != nil {
+			return err
+		}
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.tokenRepository.GetRefreshTokenByHash(hashToken(req.RefreshToken))
+	if err != nil || stored.UserID != userID {
+		return errors.New("invalid refresh token")
+	}
+
+	return s.tokenRepository.RevokeRefreshToken(stored.ID)
+}
+
+// ValidateAccessToken parses and verifies an access token and rejects it if it
+// has expired, has been revoked through logout or its session has ended.
+func (s *Service) ValidateAccessToken(tokenString string) (*model.Principal, error) {
+	token, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New("token has expired")
+		}
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	// Tokens issued before expiry or sessions were introduced carry no exp,
+	// jti or sid and would otherwise stay valid forever. Purpose tokens are
+	// never accepted.
+	jti, _ := claims["jti"].(string)
+	exp, hasExp := claims["exp"].(float64)
+	sid, _ := claims["sid"].(float64)
+	if !hasExp || jti == "" || sid == 0 || claims["purpose"] != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	revoked, err := s.tokenRepository.IsAccessTokenRevoked(jti)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// Deactivation has to take effect before outstanding tokens expire
+	userID, _ := claims["user_id"].(float64)
+	user, err := s.userRepository.GetUserByID(int(userID))
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("account is deactivated")
+	}
+
+	now := time.Now()
+	session, err := s.sessionRepository.GetSession(int(sid))
+	if err != nil || session.UserID != user.ID {
+		return nil, errors.New("invalid token")
+	}
+	if !session.IsActive(now) {
+		return nil, errors.New("session has been revoked")
+	}
+	if err := s.sessionRepository.TouchSession(session.ID, now, now.Add(-sessionTouchInterval)); err != nil {
+		return nil, err
+	}
+
+	// Role and account state come from the user row rather than the claims,
+	// so a role change or a verified email applies to outstanding tokens
+	return &model.Principal{
+		UserID:                 user.ID,
+		Username:               user.Username,
+		Role:                   user.Role,
+		EmailVerified:          user.IsEmailVerified(),
+		TwoFactorSetupRequired: s.twoFactorSetupRequired(user),
+		TokenID:                jti,
+		TokenExpiresAt:         time.Unix(int64(exp), 0),
+		SessionID:              session.ID,
+	}, nil
+}
+
+// issueTokens creates a token pair within an existing session.
+func (s *Service) issueTokens(user *model.User, sessionID int) (*model.TokenResponse, error) {
+	accessToken, expiresAt, err := s.generateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokenRepository.CreateRefreshToken(&model.RefreshToken{
+		UserID:    user.ID,
+		SessionID: &sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func (s *Service) generateToken(user *model.User, sessionID int) (string, time.Time, error) {
+	jti, err := generateRandomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(accessTokenTTL())
+	claims := jwt.MapClaims{
+		"user_id":   user.ID,
+		"username":  user.Username,
+		"role":      user.Role,
+		"verified":  user.IsEmailVerified(),
+		"2fa_setup": s.twoFactorSetupRequired(user),
+		"jti":       jti,
+		"sid":       sessionID,
+		"iat":       now.Unix(),
+		"exp":       expiresAt.Unix(),
+	}
+
+	signed, err := s.signAccessToken(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+// signPurposeToken signs a short-lived token that can only be used for the
+// given purpose, such as email verification or a two-factor challenge.
+func signPurposeToken(purpose string, userID int, ttl time.Duration, extra jwt.MapClaims) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"purpose": purpose,
+		"user_id": userID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+	}
+	for key, value := range extra {
+		claims[key] = value
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(configs.Get().JWT.SecretKey))
+}
+
+// parsePurposeToken verifies a token created by signPurposeToken and returns
+// the user ID and claims.
+func parsePurposeToken(tokenString, purpose string) (int, jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(configs.Get().JWT.SecretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return 0, nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != purpose {
+		return 0, nil, errors.New("invalid token")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, nil, errors.New("invalid token")
+	}
+
+	return int(userID), claims, nil
+}
+
+func accessTokenTTL() time.Duration {
+	if ttl := configs.Get().JWT.AccessTokenTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultAccessTokenTTL
+}
+
+func refreshTokenTTL() time.Duration {
+	if ttl := configs.Get().JWT.RefreshTokenTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultRefreshTokenTTL
+}
+
+func generateRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,374 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/ferdy-adr/elibrary-backend/pkg/qrcode"
+	"github.com/ferdy-adr/elibrary-backend/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorChallengePurpose    = "2fa_challenge"
+	defaultTwoFactorChallengeTTL = 5 * time.Minute
+	defaultTwoFactorIssuer       = "eLibrary"
+	recoveryCodeCount            = 10
+	twoFactorQRCodeScale         = 6
+)
+
+// EnrollTwoFactor generates a new TOTP secret for the user. The secret only
+// becomes active once ConfirmTwoFactor receives a valid code for it.
+func (s *Service) EnrollTwoFactor(userID int) (*model.TwoFactorEnrollment, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryptTwoFactorSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepository.SetTOTPSecret(userID, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := totp.ProvisioningURI(twoFactorIssuer(), user.Username, secret)
+	png, err := qrcode.PNG(uri, twoFactorQRCodeScale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRCode:          "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// TwoFactorQRCode renders the pending enrollment as a PNG image.
+func (s *Service) TwoFactorQRCode(userID int) ([]byte, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.IsTwoFactorEnabled() || user.TOTPSecret == nil {
+		return nil, errors.New("no pending two-factor enrollment")
+	}
+
+	secret, err := decryptTwoFactorSecret(*user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.PNG(totp.ProvisioningURI(twoFactorIssuer(), user.Username, secret), twoFactorQRCodeScale)
+}
+
+// ConfirmTwoFactor activates the pending secret after checking a code from
+// the authenticator app. It returns the recovery codes, which are shown only
+// once, and a fresh token pair reflecting the new state.
+func (s *Service) ConfirmTwoFactor(userID, sessionID int, req model.TwoFactorCodeRequest) (*model.TwoFactorActivation, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == nil {
+		return nil, errors.New("no pending two-factor enrollment")
+	}
+
+	secret, err := decryptTwoFactorSecret(*user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	err = s.userRepository.EnableTOTP(userID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorActivation{
+		RecoveryCodes: codes,
+		Tokens:        *tokens,
+	}, nil
+}
+
+func (s *Service) DisableTwoFactor(userID int, req model.DisableTwoFactorRequest) error {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !user.IsTwoFactorEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if s.twoFactorRequired(user.Role) {
+		return errors.New("two-factor authentication is required for your role")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	valid, err := s.verifyTwoFactorCode(user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid two-factor code")
+	}
+
+	err = s.userRepository.DisableTOTP(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.tokenRepository.DeleteRecoveryCodes(userID)
+}
+
+func (s *Service) RegenerateRecoveryCodes(userID int, req model.TwoFactorCodeRequest) (*model.RecoveryCodesResponse, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	valid, err := s.verifyTwoFactorCode(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	codes, err := s.generateRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// CompleteTwoFactorLogin exchanges a challenge token from Login plus a TOTP
+// or recovery code for a token pair. Wrong codes count as failed logins.
+func (s *Service) CompleteTwoFactorLogin(req model.TwoFactorLoginRequest, client model.ClientInfo) (response *model.LoginResponse, err error) {
+	var user *model.User
+	defer func() { s.recordAuthEvent(model.AuthEventTwoFactorLogin, user, "", client, err) }()
+
+	userID, _, err := parsePurposeToken(req.ChallengeToken, twoFactorChallengePurpose)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	user, err = s.userRepository.GetUserByID(userID)
+	if err != nil || !user.IsTwoFactorEnabled() {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	now := time.Now()
+	attempt, err := s.beginLoginAttempt(user.Username, client.IPAddress, now)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := s.verifyTwoFactorCode(user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if err := s.recordLoginFailure(attempt, user, now); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	if err := s.recordLoginSuccess(attempt); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(user, client)
+}
+
+func (s *Service) GetTwoFactorPolicy() (*model.TwoFactorPolicy, error) {
+	return &model.TwoFactorPolicy{RequiredRoles: s.twoFactorRequiredRoles()}, nil
+}
+
+func (s *Service) UpdateTwoFactorPolicy(adminID int, policy model.TwoFactorPolicy) (*model.TwoFactorPolicy, error) {
+	err := s.settingRepository.SetSetting(model.SettingTwoFactorRequiredRoles, strings.Join(policy.RequiredRoles, ","), adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTwoFactorPolicy()
+}
+
+// verifyTwoFactorCode accepts either a current TOTP code that has not been
+// used before or an unused recovery code.
+func (s *Service) verifyTwoFactorCode(user *model.User, code string) (bool, error) {
+	secret, err := decryptTwoFactorSecret(*user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	if step, ok := totp.Validate(secret, code, time.Now()); ok {
+		return s.userRepository.UseTOTPStep(user.ID, step)
+	}
+
+	return s.tokenRepository.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+func (s *Service) generateRecoveryCodes(userID int) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:]
+		hashes[i] = hashToken(raw)
+	}
+
+	err := s.tokenRepository.ReplaceRecoveryCodes(userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *Service) twoFactorRequiredRoles() []string {
+	value, err := s.settingRepository.GetSetting(model.SettingTwoFactorRequiredRoles)
+	if err != nil || value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+func (s *Service) twoFactorRequired(role string) bool {
+	for _, required := range s.twoFactorRequiredRoles() {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// twoFactorSetupRequired reports whether the user must enroll before using
+// privileged endpoints.
+func (s *Service) twoFactorSetupRequired(user *model.User) bool {
+	return !user.IsTwoFactorEnabled() && s.twoFactorRequired(user.Role)
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func twoFactorIssuer() string {
+	if issuer := configs.Get().Auth.TwoFactorIssuer; issuer != "" {
+		return issuer
+	}
+	return defaultTwoFactorIssuer
+}
+
+func twoFactorChallengeTTL() time.Duration {
+	if ttl := configs.Get().Auth.TwoFactorChallengeTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultTwoFactorChallengeTTL
+}
+
+// twoFactorCipher derives an AES-256-GCM cipher from auth.twoFactorKey, so a
+// database dump alone does not reveal TOTP secrets.
+func twoFactorCipher() (cipher.AEAD, error) {
+	key := configs.Get().Auth.TwoFactorKey
+	if key == "" {
+		key = configs.Get().JWT.SecretKey
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptTwoFactorSecret(secret string) (string, error) {
+	gcm, err := twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptTwoFactorSecret(encrypted string) (string, error) {
+	gcm, err := twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt two-factor secret")
+	}
+	return string(secret), nil
+}
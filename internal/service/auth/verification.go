@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/ferdy-adr/elibrary-backend/pkg/mailer"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultVerificationTTL = 24 * time.Hour
+	verifyEmailPurpose     = "verify_email"
+)
+
+// SendVerificationEmail mails a signed verification link to the user. The
+// link is bound to the current email address, so it stops working if the
+// address changes before it is used.
+func (s *Service) SendVerificationEmail(user *model.User) error {
+	if user.IsEmailVerified() {
+		return errors.New("email already verified")
+	}
+
+	ttl := verificationTTL()
+	token, err := signPurposeToken(verifyEmailPurpose, user.ID, ttl, jwt.MapClaims{"email": user.Email})
+	if err != nil {
+		return err
+	}
+
+	verifyLink := configs.Get().Auth.VerificationURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your eLibrary email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.FullName, verifyLink, ttl,
+		),
+	})
+}
+
+func (s *Service) ResendVerificationEmail(userID int) error {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.SendVerificationEmail(user)
+}
+
+func (s *Service) VerifyEmail(req model.VerifyEmailRequest) error {
+	userID, claims, err := parsePurposeToken(req.Token, verifyEmailPurpose)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	email, _ := claims["email"].(string)
+
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil || user.Email != email {
+		return errors.New("invalid or expired verification token")
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return s.userRepository.MarkEmailVerified(user.ID)
+}
+
+func verificationTTL() time.Duration {
+	if ttl := configs.Get().Auth.VerificationTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultVerificationTTL
+}
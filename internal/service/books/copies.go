@@ -0,0 +1,152 @@
+package books
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+func (s *Service) GetCopies(bookID int, params model.BookCopyQueryParams) ([]model.BookCopy, error) {
+	if _, err := s.bookRepository.GetBookByID(bookID); err != nil {
+		return nil, errors.New("book not found")
+	}
+
+	return s.copyRepository.GetCopiesByBookID(bookID, params)
+}
+
+func (s *Service) GetCopy(bookID, copyID int) (*model.BookCopy, error) {
+	bookCopy, err := s.copyRepository.GetCopy(copyID)
+	if err != nil || bookCopy.BookID != bookID {
+		return nil, errors.New("copy not found")
+	}
+	return bookCopy, nil
+}
+
+func (s *Service) CreateCopy(bookID int, req model.CreateBookCopyRequest) (*model.BookCopy, error) {
+	if _, err := s.bookRepository.GetBookByID(bookID); err != nil {
+		return nil, errors.New("book not found")
+	}
+
+	exists, err := s.copyRepository.CheckBarcodeExists(req.Barcode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("barcode already exists")
+	}
+
+	bookCopy := &model.BookCopy{
+		BookID:        bookID,
+		Barcode:       req.Barcode,
+		Condition:     req.Condition,
+		ShelfLocation: req.ShelfLocation,
+		Status:        req.Status,
+	}
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = model.CopyConditionGood
+	}
+	if bookCopy.Status == "" {
+		bookCopy.Status = model.CopyStatusAvailable
+	}
+	if req.AcquiredAt != "" {
+		acquiredAt, err := time.Parse("2006-01-02", req.AcquiredAt)
+		if err != nil {
+			return nil, errors.New("acquired_at must be a date in YYYY-MM-DD format")
+		}
+		bookCopy.AcquiredAt = &acquiredAt
+	}
+
+	err = s.copyRepository.CreateCopy(bookCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.offerToHolds(bookCopy.ID, bookCopy.Status); err != nil {
+		return nil, err
+	}
+
+	return s.copyRepository.GetCopy(bookCopy.ID)
+}
+
+func (s *Service) UpdateCopy(bookID, copyID int, req model.UpdateBookCopyRequest) (*model.BookCopy, error) {
+	existingCopy, err := s.GetCopy(bookID, copyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Barcode != "" && req.Barcode != existingCopy.Barcode {
+		exists, err := s.copyRepository.CheckBarcodeExists(req.Barcode, copyID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("barcode already exists")
+		}
+	}
+
+	// A copy on loan comes back through a return, not a status edit
+	if req.Status != "" && existingCopy.Status == model.CopyStatusOnLoan {
+		return nil, errors.New("copy is on loan")
+	}
+	if req.Status != "" && existingCopy.Status == model.CopyStatusOnHold {
+		return nil, errors.New("copy is on hold")
+	}
+
+	bookCopy := &model.BookCopy{
+		Barcode:   req.Barcode,
+		Condition: req.Condition,
+		Status:    req.Status,
+	}
+	if req.ShelfLocation != nil {
+		bookCopy.ShelfLocation = *req.ShelfLocation
+	}
+	if req.AcquiredAt != "" {
+		acquiredAt, err := time.Parse("2006-01-02", req.AcquiredAt)
+		if err != nil {
+			return nil, errors.New("acquired_at must be a date in YYYY-MM-DD format")
+		}
+		bookCopy.AcquiredAt = &acquiredAt
+	}
+
+	updated, err := s.copyRepository.UpdateCopy(copyID, bookCopy, req.ShelfLocation != nil, existingCopy.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("copy was changed, please try again")
+	}
+
+	if req.Status != "" && req.Status != existingCopy.Status {
+		if err := s.offerToHolds(copyID, req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.copyRepository.GetCopy(copyID)
+}
+
+// offerToHolds sets a copy that just became available aside for the first
+// waiting hold, as a return does, so the queue is served before the desk.
+func (s *Service) offerToHolds(copyID int, status string) error {
+	if status != model.CopyStatusAvailable {
+		return nil
+	}
+	_, err := s.holdService.ShelveCopy(copyID)
+	return err
+}
+
+func (s *Service) DeleteCopy(bookID, copyID int) error {
+	bookCopy, err := s.GetCopy(bookID, copyID)
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status == model.CopyStatusOnLoan {
+		return errors.New("copy is on loan")
+	}
+	if bookCopy.Status == model.CopyStatusOnHold {
+		return errors.New("copy is on hold")
+	}
+
+	return s.copyRepository.DeleteCopy(copyID)
+}
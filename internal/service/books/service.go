@@ -13,15 +13,47 @@ import (
 	"github.com/ferdy-adr/elibrary-backend/internal/configs"
 	"github.com/ferdy-adr/elibrary-backend/internal/model"
 	bookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/books"
+	copyRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/copies"
+	holdService "github.com/ferdy-adr/elibrary-backend/internal/service/holds"
 )
 
+// EventHandler is called after a book is created, updated or deleted. It
+// runs on the request goroutine, so slow work belongs in a goroutine.
+type EventHandler func(event model.BookEvent)
+
 type Service struct {
 	bookRepository *bookRepo.Repository
+	copyRepository *copyRepo.Repository
+	holdService    *holdService.Service
+	eventHandlers  []EventHandler
 }
 
-func NewService(bookRepository *bookRepo.Repository) *Service {
+func NewService(
+	bookRepository *bookRepo.Repository,
+	copyRepository *copyRepo.Repository,
+	holdService *holdService.Service,
+) *Service {
 	return &Service{
 		bookRepository: bookRepository,
+		copyRepository: copyRepository,
+		holdService:    holdService,
+	}
+}
+
+// OnEvent registers a handler for catalog events. Handlers must be
+// registered before the service handles requests.
+func (s *Service) OnEvent(handler EventHandler) {
+	s.eventHandlers = append(s.eventHandlers, handler)
+}
+
+func (s *Service) emit(eventType string, book *model.Book) {
+	event := model.BookEvent{
+		Type:       eventType,
+		Book:       *book,
+		OccurredAt: time.Now(),
+	}
+	for _, handler := range s.eventHandlers {
+		handler(event)
 	}
 }
 
@@ -62,6 +94,12 @@ func (s *Service) CreateBook(req model.CreateBookRequest, coverFile *multipart.F
 		return nil, err
 	}
 
+	// Reload for the timestamps and copy counts set by the database
+	if created, err := s.bookRepository.GetBookByID(book.ID); err == nil {
+		book = created
+	}
+
+	s.emit(model.BookEventCreated, book)
 	return book, nil
 }
 
@@ -149,7 +187,13 @@ func (s *Service) UpdateBook(id int, req model.UpdateBookRequest, coverFile *mul
 	}
 
 	// Get updated book
-	return s.bookRepository.GetBookByID(id)
+	updated, err := s.bookRepository.GetBookByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.emit(model.BookEventUpdated, updated)
+	return updated, nil
 }
 
 func (s *Service) DeleteBook(id int) error {
@@ -159,6 +203,15 @@ func (s *Service) DeleteBook(id int) error {
 		return errors.New("book not found")
 	}
 
+	// Deleting the book would also delete copies that are still out
+	onLoan, err := s.copyRepository.GetCopiesByBookID(id, model.BookCopyQueryParams{Status: model.CopyStatusOnLoan})
+	if err != nil {
+		return err
+	}
+	if len(onLoan) > 0 {
+		return errors.New("book has copies on loan")
+	}
+
 	// Delete from database
 	err = s.bookRepository.DeleteBook(id)
 	if err != nil {
@@ -170,6 +223,7 @@ func (s *Service) DeleteBook(id int) error {
 		s.deleteCoverImage(book.CoverImage)
 	}
 
+	s.emit(model.BookEventDeleted, book)
 	return nil
 }
 
@@ -0,0 +1,37 @@
+package fines
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// ExportAccountData adds the user's fines to a personal data export.
+func (s *Service) ExportAccountData(userID int, export *model.AccountExport) error {
+	fines, err := s.fineRepository.GetFinesByUserID(userID)
+	if err != nil {
+		return err
+	}
+	export.Fines = fines
+	return nil
+}
+
+// CheckAccountDeletion refuses to delete an account with an outstanding
+// balance.
+func (s *Service) CheckAccountDeletion(tx *sql.Tx, userID int) error {
+	balance, err := s.fineRepository.GetOutstandingBalance(tx, userID)
+	if err != nil {
+		return err
+	}
+	if balance > 0 {
+		return errors.New("account has outstanding fines")
+	}
+	return nil
+}
+
+// DeleteAccountData keeps the user's fines in the ledger, pointing at the
+// anonymized user.
+func (s *Service) DeleteAccountData(tx *sql.Tx, userID int) error {
+	return nil
+}
@@ -0,0 +1,212 @@
+package fines
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	fineRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/fines"
+	loanRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/loans"
+)
+
+type Service struct {
+	fineRepository *fineRepo.Repository
+	loanRepository *loanRepo.Repository
+}
+
+func NewService(fineRepository *fineRepo.Repository, loanRepository *loanRepo.Repository) *Service {
+	return &Service{
+		fineRepository: fineRepository,
+		loanRepository: loanRepository,
+	}
+}
+
+// calculateFine charges the daily rate for every started day the loan is
+// late beyond the grace period, up to the cap.
+func calculateFine(dueAt, returnedAt time.Time) (amount, daysOverdue int) {
+	policy := configs.Get().Fines
+	if policy.DailyRate <= 0 {
+		return 0, 0
+	}
+
+	late := returnedAt.Sub(dueAt) - policy.GracePeriod
+	if late <= 0 {
+		return 0, 0
+	}
+
+	const day = 24 * time.Hour
+	daysOverdue = int((late + day - 1) / day)
+	amount = daysOverdue * policy.DailyRate
+	if policy.MaxAmount > 0 && amount > policy.MaxAmount {
+		amount = policy.MaxAmount
+	}
+
+	return amount, daysOverdue
+}
+
+// AssessReturn adds the fine for a returned loan to the ledger as part of
+// the return's transaction. It returns nil when the loan came back in time;
+// the fine can be read with GetFine once the transaction is committed.
+func (s *Service) AssessReturn(tx *sql.Tx, loan *model.Loan) (*model.Fine, error) {
+	if loan.ReturnedAt == nil {
+		return nil, errors.New("loan is not returned")
+	}
+
+	amount, daysOverdue := calculateFine(loan.DueAt, *loan.ReturnedAt)
+	if amount == 0 {
+		return nil, nil
+	}
+
+	fine := &model.Fine{
+		UserID:      loan.UserID,
+		LoanID:      &loan.ID,
+		Amount:      amount,
+		DaysOverdue: daysOverdue,
+		Status:      model.FineStatusOutstanding,
+	}
+	err := s.fineRepository.CreateFine(tx, fine)
+	if err != nil {
+		return nil, err
+	}
+
+	return fine, nil
+}
+
+func (s *Service) GetFine(id int) (*model.Fine, error) {
+	fine, err := s.fineRepository.GetFine(id)
+	if err != nil {
+		return nil, errors.New("fine not found")
+	}
+	return fine, nil
+}
+
+// GetLedger lists the user's fines together with what their overdue loans
+// have accrued so far.
+func (s *Service) GetLedger(userID int) (*model.FineLedger, error) {
+	fines, err := s.fineRepository.GetFinesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := &model.FineLedger{
+		UserID:   userID,
+		Fines:    fines,
+		Accruing: []model.AccruingFine{},
+	}
+	for _, fine := range fines {
+		if fine.Status == model.FineStatusOutstanding {
+			ledger.OutstandingBalance += fine.Amount
+		}
+	}
+
+	now := time.Now()
+	loans, err := s.loanRepository.GetOverdueLoansByUserID(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		amount, daysOverdue := calculateFine(loan.DueAt, now)
+		if amount == 0 {
+			continue
+		}
+		loan.Overdue = true
+		ledger.Accruing = append(ledger.Accruing, model.AccruingFine{
+			Loan:        loan,
+			Amount:      amount,
+			DaysOverdue: daysOverdue,
+		})
+		ledger.AccruingBalance += amount
+	}
+
+	ledger.Balance = ledger.OutstandingBalance + ledger.AccruingBalance
+	ledger.BorrowingBlocked = isBlocked(ledger.Balance)
+	return ledger, nil
+}
+
+func isBlocked(balance int) bool {
+	limit := configs.Get().Fines.BlockBalance
+	return limit > 0 && balance > limit
+}
+
+// IsBorrowingBlocked reports whether the user's balance, including fines
+// still accruing, is above the configured limit.
+func (s *Service) IsBorrowingBlocked(userID int) (bool, error) {
+	if configs.Get().Fines.BlockBalance <= 0 {
+		return false, nil
+	}
+
+	ledger, err := s.GetLedger(userID)
+	if err != nil {
+		return false, err
+	}
+	return ledger.BorrowingBlocked, nil
+}
+
+// CheckBorrowing refuses new loans while borrowing is blocked by fines.
+func (s *Service) CheckBorrowing(userID int) error {
+	blocked, err := s.IsBorrowingBlocked(userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.New("fine balance exceeds the borrowing limit")
+	}
+
+	return nil
+}
+
+func (s *Service) GetFines(params model.FineQueryParams) (*model.FineListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	fines, total, err := s.fineRepository.GetFines(params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.FineListResponse{
+		Fines:      fines,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// SettleFine records that the fine was paid at the desk.
+func (s *Service) SettleFine(id, staffID int, req model.SettleFineRequest) (*model.Fine, error) {
+	return s.resolveFine(id, model.FineStatusPaid, req.Note, staffID)
+}
+
+// WaiveFine cancels the fine without payment. The reason is kept in the ledger.
+func (s *Service) WaiveFine(id, staffID int, req model.WaiveFineRequest) (*model.Fine, error) {
+	return s.resolveFine(id, model.FineStatusWaived, req.Reason, staffID)
+}
+
+func (s *Service) resolveFine(id int, status, note string, staffID int) (*model.Fine, error) {
+	if _, err := s.fineRepository.GetFine(id); err != nil {
+		return nil, errors.New("fine not found")
+	}
+
+	resolved, err := s.fineRepository.ResolveFine(id, status, note, staffID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, errors.New("fine is already resolved")
+	}
+
+	return s.fineRepository.GetFine(id)
+}
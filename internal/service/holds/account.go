@@ -0,0 +1,29 @@
+package holds
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// ExportAccountData adds the user's holds to a personal data export.
+func (s *Service) ExportAccountData(userID int, export *model.AccountExport) error {
+	holds, err := s.holdRepository.GetHoldsByUserID(userID)
+	if err != nil {
+		return err
+	}
+	export.Holds = holds
+	return nil
+}
+
+// CheckAccountDeletion never blocks a deletion; open holds are closed with
+// the account.
+func (s *Service) CheckAccountDeletion(tx *sql.Tx, userID int) error {
+	return nil
+}
+
+// DeleteAccountData takes the user out of every queue.
+func (s *Service) DeleteAccountData(tx *sql.Tx, userID int) error {
+	return s.holdRepository.CloseUserHolds(tx, userID, time.Now())
+}
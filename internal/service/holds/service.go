@@ -0,0 +1,257 @@
+package holds
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	bookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/books"
+	holdRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/holds"
+	loanRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/loans"
+	notificationService "github.com/ferdy-adr/elibrary-backend/internal/service/notifications"
+	tierService "github.com/ferdy-adr/elibrary-backend/internal/service/tiers"
+)
+
+const defaultPickupPeriod = 3 * 24 * time.Hour
+
+type Service struct {
+	holdRepository      *holdRepo.Repository
+	bookRepository      *bookRepo.Repository
+	loanRepository      *loanRepo.Repository
+	tierService         *tierService.Service
+	notificationService *notificationService.Service
+}
+
+func NewService(
+	holdRepository *holdRepo.Repository,
+	bookRepository *bookRepo.Repository,
+	loanRepository *loanRepo.Repository,
+	tierService *tierService.Service,
+	notificationService *notificationService.Service,
+) *Service {
+	return &Service{
+		holdRepository:      holdRepository,
+		bookRepository:      bookRepository,
+		loanRepository:      loanRepository,
+		tierService:         tierService,
+		notificationService: notificationService,
+	}
+}
+
+func pickupPeriod() time.Duration {
+	if period := configs.Get().Holds.PickupPeriod; period > 0 {
+		return period
+	}
+	return defaultPickupPeriod
+}
+
+// PlaceHold queues the member for a book whose copies are all out.
+func (s *Service) PlaceHold(userID int, req model.PlaceHoldRequest) (*model.Hold, error) {
+	book, err := s.bookRepository.GetBookByID(req.BookID)
+	if err != nil {
+		return nil, errors.New("book not found")
+	}
+	if book.TotalCopies == 0 {
+		return nil, errors.New("book has no copies")
+	}
+	if book.AvailableCopies > 0 {
+		return nil, errors.New("book has available copies")
+	}
+
+	exists, err := s.holdRepository.HasOpenHold(userID, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("hold already placed")
+	}
+
+	onLoan, err := s.loanRepository.HasActiveLoanForBook(userID, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	if onLoan {
+		return nil, errors.New("book is already on loan to you")
+	}
+
+	tier, err := s.tierService.GetUserTier(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tier != nil {
+		openHolds, err := s.holdRepository.CountOpenHoldsByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if openHolds >= tier.HoldLimit {
+			return nil, errors.New("hold limit reached for membership tier")
+		}
+	}
+
+	hold := &model.Hold{
+		BookID: book.ID,
+		UserID: userID,
+		Status: model.HoldStatusWaiting,
+	}
+	err = s.holdRepository.CreateHold(hold)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.holdRepository.GetHold(hold.ID)
+}
+
+// CancelHold withdraws a hold. Members may only cancel their own, staff may
+// cancel any. A copy set aside for the hold goes to the next member.
+func (s *Service) CancelHold(id, userID int, staff bool) (*model.Hold, error) {
+	hold, err := s.holdRepository.GetHold(id)
+	if err != nil || (!staff && hold.UserID != userID) {
+		return nil, errors.New("hold not found")
+	}
+
+	cancelled, err := s.holdRepository.CloseHold(id, model.HoldStatusCancelled, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, errors.New("hold is already closed")
+	}
+
+	if hold.Status == model.HoldStatusReady && hold.CopyID != nil {
+		if _, err := s.ShelveCopy(*hold.CopyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.holdRepository.GetHold(id)
+}
+
+func (s *Service) GetHolds(params model.HoldQueryParams) (*model.HoldListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	holds, total, err := s.holdRepository.GetHolds(params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.HoldListResponse{
+		Holds:      holds,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetUserHolds lists the user's own holds with their queue positions.
+func (s *Service) GetUserHolds(userID int, params model.HoldQueryParams) (*model.HoldListResponse, error) {
+	params.UserID = userID
+	return s.GetHolds(params)
+}
+
+// GetReadyHold returns the hold a copy on the hold shelf is set aside for.
+func (s *Service) GetReadyHold(copyID int) (*model.Hold, error) {
+	hold, err := s.holdRepository.GetReadyHoldByCopyID(copyID)
+	if err != nil {
+		return nil, errors.New("hold not found")
+	}
+	return hold, nil
+}
+
+// HasWaitingHolds reports whether another member is queued for the book.
+func (s *Service) HasWaitingHolds(bookID, excludeUserID int) (bool, error) {
+	return s.holdRepository.HasWaitingHolds(bookID, excludeUserID)
+}
+
+// ShelveCopy serves the holds queue with a copy that just became free. It
+// returns the hold the copy was set aside for, or nil when nobody is
+// waiting and the copy is available again.
+func (s *Service) ShelveCopy(copyID int) (*model.Hold, error) {
+	now := time.Now()
+	holdID, err := s.holdRepository.ShelveCopy(copyID, now, now.Add(pickupPeriod()))
+	if err != nil || holdID == 0 {
+		return nil, err
+	}
+
+	return s.HoldReady(holdID)
+}
+
+// ShelveCopyTx is ShelveCopy as part of the caller's transaction. It
+// returns the ID of the hold the copy was set aside for, or 0; once the
+// transaction is committed the caller passes it to HoldReady.
+func (s *Service) ShelveCopyTx(tx *sql.Tx, copyID int) (int, error) {
+	now := time.Now()
+	return s.holdRepository.ShelveCopyTx(tx, copyID, now, now.Add(pickupPeriod()))
+}
+
+// FulfillHoldTx closes the member's hold on a book as part of the
+// caller's checkout transaction.
+func (s *Service) FulfillHoldTx(tx *sql.Tx, userID, bookID, copyID int, fulfilledAt time.Time) error {
+	return s.holdRepository.FulfillHoldTx(tx, userID, bookID, copyID, fulfilledAt)
+}
+
+// HoldReady tells the member that their hold is waiting on the hold shelf.
+func (s *Service) HoldReady(holdID int) (*model.Hold, error) {
+	hold, err := s.holdRepository.GetHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The copy stays on the shelf either way, so a failed notification is
+	// only logged
+	title := fmt.Sprintf("%s is ready for pickup", hold.BookTitle)
+	body := fmt.Sprintf(
+		"Copy %s of %s is waiting for you on the hold shelf. Please pick it up before %s.",
+		hold.Barcode, hold.BookTitle, hold.PickupDeadline.Format("2 January 2006 15:04"),
+	)
+	if err := s.notificationService.Notify(hold.UserID, model.NotificationTypeHoldReady, title, body, &hold.BookID); err != nil {
+		log.Printf("Failed to notify user %d of ready hold %d: %v", hold.UserID, hold.ID, err)
+	}
+
+	return hold, nil
+}
+
+// ExpireHolds closes ready holds that were not picked up in time and passes
+// their copies on to the next member in the queue.
+func (s *Service) ExpireHolds() (int, error) {
+	now := time.Now()
+	holds, err := s.holdRepository.GetExpiredHolds(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, hold := range holds {
+		closed, err := s.holdRepository.CloseHold(hold.ID, model.HoldStatusExpired, now)
+		if err != nil {
+			return expired, err
+		}
+		if !closed {
+			continue
+		}
+		expired++
+
+		if hold.CopyID != nil {
+			if _, err := s.ShelveCopy(*hold.CopyID); err != nil {
+				return expired, err
+			}
+		}
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,250 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	jobRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/jobs"
+	"github.com/ferdy-adr/elibrary-backend/pkg/cron"
+	"github.com/ferdy-adr/elibrary-backend/pkg/textutil"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultLockTTL      = 10 * time.Minute
+
+	// maxResultLength fits the last_result column
+	maxResultLength = 255
+)
+
+// JobFunc does one run of a job. The returned string is a short summary
+// shown to admins, such as how many records were processed.
+type JobFunc func() (string, error)
+
+type job struct {
+	name     string
+	spec     string
+	schedule *cron.Schedule
+	run      JobFunc
+}
+
+// Service runs registered jobs on their cron schedules. Every replica runs
+// the same loop; a lock on the job's row in scheduled_jobs makes sure only
+// one of them fires each run.
+type Service struct {
+	jobRepository *jobRepo.Repository
+	owner         string
+
+	mu    sync.RWMutex
+	jobs  map[string]*job
+	order []string
+}
+
+func NewService(jobRepository *jobRepo.Repository) *Service {
+	return &Service{
+		jobRepository: jobRepository,
+		owner:         newOwnerID(),
+		jobs:          make(map[string]*job),
+	}
+}
+
+// newOwnerID identifies this process in job locks.
+func newOwnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+func pollInterval() time.Duration {
+	if interval := configs.Get().Scheduler.PollInterval; interval > 0 {
+		return interval
+	}
+	return defaultPollInterval
+}
+
+func lockTTL() time.Duration {
+	if ttl := configs.Get().Scheduler.LockTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultLockTTL
+}
+
+// Register adds a job with a cron expression such as "0 8 * * *" and
+// records it in scheduled_jobs.
+func (s *Service) Register(name, spec string, run JobFunc) error {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %v", name, err)
+	}
+
+	nextRunAt := schedule.Next(time.Now())
+	if nextRunAt.IsZero() {
+		return fmt.Errorf("job %s: schedule %q never fires", name, spec)
+	}
+	if err := s.jobRepository.UpsertJob(name, spec, nextRunAt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; !exists {
+		s.order = append(s.order, name)
+	}
+	s.jobs[name] = &job{name: name, spec: spec, schedule: schedule, run: run}
+	return nil
+}
+
+// Run polls for due jobs until the process exits. It blocks, so run it in
+// its own goroutine.
+func (s *Service) Run() {
+	ticker := time.NewTicker(pollInterval())
+	defer ticker.Stop()
+
+	s.runDueJobs()
+	for range ticker.C {
+		s.runDueJobs()
+	}
+}
+
+func (s *Service) runDueJobs() {
+	s.mu.RLock()
+	jobs := make([]*job, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.mu.RUnlock()
+
+	for _, j := range jobs {
+		if err := s.runIfDue(j); err != nil {
+			log.Printf("Scheduler: job %s: %v", j.name, err)
+		}
+	}
+}
+
+// runIfDue runs the job when it is due and no other replica holds it.
+func (s *Service) runIfDue(j *job) error {
+	now := time.Now()
+	claimed, err := s.jobRepository.ClaimJob(j.name, s.owner, now, now.Add(lockTTL()))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		s.keepLock(j.name, stop)
+		close(stopped)
+	}()
+
+	result, runErr := runJob(j)
+	close(stop)
+	<-stopped
+	finishedAt := time.Now()
+	durationMs := int(finishedAt.Sub(now).Milliseconds())
+
+	outcome := &model.ScheduledJob{
+		NextRunAt:      j.schedule.Next(finishedAt),
+		LastFinishedAt: &finishedAt,
+		LastStatus:     model.JobStatusSucceeded,
+		LastResult:     textutil.Truncate(result, maxResultLength),
+		LastDurationMs: &durationMs,
+	}
+	if runErr != nil {
+		outcome.LastStatus = model.JobStatusFailed
+		outcome.LastError = runErr.Error()
+		log.Printf("Scheduler: job %s failed: %v", j.name, runErr)
+	}
+
+	return s.jobRepository.FinishJob(j.name, s.owner, outcome)
+}
+
+// keepLock extends the job's lock every third of the lock TTL until stop
+// is closed, so a run that outlasts the TTL is not started again by
+// another replica. The lock still expires if this replica dies.
+func (s *Service) keepLock(name string, stop <-chan struct{}) {
+	ttl := lockTTL()
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			extended, err := s.jobRepository.ExtendJobLock(name, s.owner, now.Add(ttl))
+			if err != nil {
+				log.Printf("Scheduler: job %s: failed to extend lock: %v", name, err)
+				continue
+			}
+			if !extended {
+				log.Printf("Scheduler: job %s lost its lock", name)
+				return
+			}
+		}
+	}
+}
+
+// runJob calls the job, turning a panic into a failed run so the lock is
+// still released.
+func runJob(j *job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run()
+}
+
+// GetJobs lists the jobs with their last run.
+func (s *Service) GetJobs() ([]model.ScheduledJob, error) {
+	jobs, err := s.jobRepository.GetJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range jobs {
+		jobs[i].Running = jobs[i].LockedUntil != nil && jobs[i].LockedUntil.After(now)
+	}
+	return jobs, nil
+}
+
+// RunNow makes a job due immediately. It runs on the next poll of whichever
+// replica claims it first, so the lock still prevents a double run.
+func (s *Service) RunNow(name string) (*model.ScheduledJob, error) {
+	s.mu.RLock()
+	_, registered := s.jobs[name]
+	s.mu.RUnlock()
+	if !registered {
+		return nil, errors.New("job not found")
+	}
+
+	if err := s.jobRepository.TriggerJob(name, time.Now()); err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobRepository.GetJob(name)
+	if err != nil {
+		return nil, err
+	}
+	job.Running = job.LockedUntil != nil && job.LockedUntil.After(time.Now())
+	return job, nil
+}
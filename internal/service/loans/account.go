@@ -0,0 +1,37 @@
+package loans
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// ExportAccountData adds the user's loans to a personal data export.
+func (s *Service) ExportAccountData(userID int, export *model.AccountExport) error {
+	loans, err := s.loanRepository.GetLoansByUserID(userID)
+	if err != nil {
+		return err
+	}
+	export.Loans = loans
+	return nil
+}
+
+// CheckAccountDeletion refuses to delete an account while it has copies on
+// loan; they have to come back first.
+func (s *Service) CheckAccountDeletion(tx *sql.Tx, userID int) error {
+	activeLoans, err := s.loanRepository.CountActiveLoansByUserID(tx, userID)
+	if err != nil {
+		return err
+	}
+	if activeLoans > 0 {
+		return errors.New("account has books on loan")
+	}
+	return nil
+}
+
+// DeleteAccountData keeps the user's loans, which are part of the
+// circulation history and go on pointing at the anonymized user.
+func (s *Service) DeleteAccountData(tx *sql.Tx, userID int) error {
+	return nil
+}
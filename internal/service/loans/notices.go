@@ -0,0 +1,96 @@
+package loans
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+const defaultReminderLead = 2 * 24 * time.Hour
+
+func reminderLead() time.Duration {
+	if lead := configs.Get().Scheduler.ReminderLead; lead > 0 {
+		return lead
+	}
+	return defaultReminderLead
+}
+
+// SendDueReminders notifies borrowers whose loans fall due within the
+// reminder lead time. Each loan is reminded about once per due date.
+func (s *Service) SendDueReminders() (string, error) {
+	now := time.Now()
+	loans, err := s.loanRepository.GetLoansDueForReminder(now, now.Add(reminderLead()))
+	if err != nil {
+		return "", err
+	}
+
+	sent, failed := s.sendNotices(loans, now, s.loanRepository.MarkReminderSent, func(loan *model.Loan) (string, string, string) {
+		return model.NotificationTypeDueReminder,
+			fmt.Sprintf("Reminder: %s is due soon", loan.BookTitle),
+			fmt.Sprintf(
+				"%s (copy %s) is due back on %s. Please return or renew it before then to avoid a late fine.",
+				loan.BookTitle, loan.Barcode, loan.DueAt.Format("2 January 2006 15:04"),
+			)
+	})
+
+	return noticeResult("reminders", sent, failed)
+}
+
+// SendOverdueNotices notifies borrowers once for each loan that has gone
+// past its due date.
+func (s *Service) SendOverdueNotices() (string, error) {
+	now := time.Now()
+	loans, err := s.loanRepository.GetLoansDueForOverdueNotice(now)
+	if err != nil {
+		return "", err
+	}
+
+	sent, failed := s.sendNotices(loans, now, s.loanRepository.MarkOverdueNoticeSent, func(loan *model.Loan) (string, string, string) {
+		return model.NotificationTypeOverdueNotice,
+			fmt.Sprintf("Overdue: %s", loan.BookTitle),
+			fmt.Sprintf(
+				"%s (copy %s) was due back on %s and is now overdue. Please return it as soon as possible; late fines are charged for every day it is overdue.",
+				loan.BookTitle, loan.Barcode, loan.DueAt.Format("2 January 2006 15:04"),
+			)
+	})
+
+	return noticeResult("overdue notices", sent, failed)
+}
+
+// sendNotices notifies each loan's borrower and marks the loan once the
+// notification is recorded.
+func (s *Service) sendNotices(
+	loans []model.Loan,
+	now time.Time,
+	markSent func(id int, sentAt time.Time) error,
+	build func(loan *model.Loan) (notificationType, title, body string),
+) (sent, failed int) {
+	for i := range loans {
+		loan := &loans[i]
+
+		notificationType, title, body := build(loan)
+		if err := s.notificationService.Notify(loan.UserID, notificationType, title, body, loan.BookID); err != nil {
+			log.Printf("Failed to notify borrower of loan %d: %v", loan.ID, err)
+			failed++
+			continue
+		}
+		sent++
+
+		if err := markSent(loan.ID, now); err != nil {
+			log.Printf("Failed to mark notice sent for loan %d: %v", loan.ID, err)
+		}
+	}
+
+	return sent, failed
+}
+
+func noticeResult(kind string, sent, failed int) (string, error) {
+	result := fmt.Sprintf("%d %s sent", sent, kind)
+	if failed > 0 {
+		return result, fmt.Errorf("%d %s could not be sent", failed, kind)
+	}
+	return result, nil
+}
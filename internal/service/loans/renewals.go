@@ -0,0 +1,145 @@
+package loans
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+const (
+	defaultMaxRenewals         = 2
+	defaultRenewalOverdueLimit = 72 * time.Hour
+)
+
+// RenewalRefusedError lists every reason a renewal was refused, so members
+// know what to do before trying again.
+type RenewalRefusedError struct {
+	Reasons []model.RenewalRefusal
+}
+
+func (e *RenewalRefusedError) Error() string {
+	messages := make([]string, len(e.Reasons))
+	for i, reason := range e.Reasons {
+		messages[i] = reason.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func renewalPeriod() time.Duration {
+	if period := configs.Get().Loans.RenewalPeriod; period > 0 {
+		return period
+	}
+	return loanPeriod()
+}
+
+// maxRenewals and renewalOverdueLimit honor a configured zero, which
+// turns renewals off or refuses overdue loans; only an unset key falls
+// back to the default.
+func maxRenewals() int {
+	if renewals := configs.Get().Loans.MaxRenewals; renewals != nil {
+		return *renewals
+	}
+	return defaultMaxRenewals
+}
+
+func renewalOverdueLimit() time.Duration {
+	if limit := configs.Get().Loans.RenewalOverdueLimit; limit != nil {
+		return *limit
+	}
+	return defaultRenewalOverdueLimit
+}
+
+// RenewLoan extends the due date of an active loan by the member's tier
+// loan length, or the renewal period without a tier, counted from the
+// current due date or from now when the loan is overdue.
+// Members may only renew their own loans; staff may renew any.
+func (s *Service) RenewLoan(id, userID int, staff bool) (*model.Loan, error) {
+	loan, err := s.GetLoan(id)
+	if err != nil || (!staff && loan.UserID != userID) {
+		return nil, errors.New("loan not found")
+	}
+	if !loan.IsActive() {
+		return nil, errors.New("loan is already returned")
+	}
+
+	now := time.Now()
+	reasons, err := s.renewalRefusals(loan, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(reasons) > 0 {
+		return nil, &RenewalRefusedError{Reasons: reasons}
+	}
+
+	period := renewalPeriod()
+	tier, err := s.tierService.GetUserTier(loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if tier != nil {
+		period = tier.LoanPeriod()
+	}
+
+	dueAt := loan.DueAt
+	if now.After(dueAt) {
+		dueAt = now
+	}
+
+	renewed, err := s.loanRepository.RenewLoan(id, loan.RenewalCount, dueAt.Add(period), now)
+	if err != nil {
+		return nil, err
+	}
+	if !renewed {
+		return nil, errors.New("loan was changed, please try again")
+	}
+
+	return s.GetLoan(id)
+}
+
+func (s *Service) renewalRefusals(loan *model.Loan, now time.Time) ([]model.RenewalRefusal, error) {
+	reasons := []model.RenewalRefusal{}
+
+	if loan.RenewalCount >= maxRenewals() {
+		reasons = append(reasons, model.RenewalRefusal{
+			Reason:  model.RenewalRefusedMaxRenewals,
+			Message: fmt.Sprintf("the loan has already been renewed the maximum of %d times", maxRenewals()),
+		})
+	}
+
+	if now.Sub(loan.DueAt) > renewalOverdueLimit() {
+		reasons = append(reasons, model.RenewalRefusal{
+			Reason:  model.RenewalRefusedOverdue,
+			Message: "the loan is too far overdue to be renewed, please return it",
+		})
+	}
+
+	if loan.BookID != nil {
+		waiting, err := s.holdService.HasWaitingHolds(*loan.BookID, loan.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if waiting {
+			reasons = append(reasons, model.RenewalRefusal{
+				Reason:  model.RenewalRefusedOnHold,
+				Message: "another member is waiting for this book",
+			})
+		}
+	}
+
+	blocked, err := s.fineService.IsBorrowingBlocked(loan.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		reasons = append(reasons, model.RenewalRefusal{
+			Reason:  model.RenewalRefusedFines,
+			Message: "the fine balance exceeds the borrowing limit",
+		})
+	}
+
+	return reasons, nil
+}
@@ -0,0 +1,300 @@
+package loans
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	copyRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/copies"
+	loanRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/loans"
+	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
+	fineService "github.com/ferdy-adr/elibrary-backend/internal/service/fines"
+	holdService "github.com/ferdy-adr/elibrary-backend/internal/service/holds"
+	notificationService "github.com/ferdy-adr/elibrary-backend/internal/service/notifications"
+	tierService "github.com/ferdy-adr/elibrary-backend/internal/service/tiers"
+)
+
+const defaultLoanPeriod = 14 * 24 * time.Hour
+
+type Service struct {
+	loanRepository      *loanRepo.Repository
+	copyRepository      *copyRepo.Repository
+	userRepository      *userRepo.Repository
+	holdService         *holdService.Service
+	fineService         *fineService.Service
+	tierService         *tierService.Service
+	notificationService *notificationService.Service
+}
+
+func NewService(
+	loanRepository *loanRepo.Repository,
+	copyRepository *copyRepo.Repository,
+	userRepository *userRepo.Repository,
+	holdService *holdService.Service,
+	fineService *fineService.Service,
+	tierService *tierService.Service,
+	notificationService *notificationService.Service,
+) *Service {
+	return &Service{
+		loanRepository:      loanRepository,
+		copyRepository:      copyRepository,
+		userRepository:      userRepository,
+		holdService:         holdService,
+		fineService:         fineService,
+		tierService:         tierService,
+		notificationService: notificationService,
+	}
+}
+
+func loanPeriod() time.Duration {
+	if period := configs.Get().Loans.LoanPeriod; period > 0 {
+		return period
+	}
+	return defaultLoanPeriod
+}
+
+// Checkout lends a copy, found by ID or barcode, to a member and fulfills
+// their hold on the book in the same transaction. staffID is the librarian
+// handing it out.
+func (s *Service) Checkout(req model.CheckoutRequest, staffID int) (*model.Loan, error) {
+	user, err := s.userRepository.GetUserByID(req.UserID)
+	if err != nil || user.IsDeleted() {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("user account is deactivated")
+	}
+	if !user.IsEmailVerified() {
+		return nil, errors.New("email address is not verified")
+	}
+	if err := s.fineService.CheckBorrowing(user.ID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.loanRepository.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the member serializes their checkouts with each other and with
+	// an account deletion, so neither the loan limit nor the deletion's
+	// active loan check counts loans that are about to change
+	if _, err := s.userRepository.LockUser(tx, user.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	// The member's tier caps concurrent loans and sets the loan length
+	period := loanPeriod()
+	tier, err := s.tierService.GetUserTier(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tier != nil {
+		activeLoans, err := s.loanRepository.CountActiveLoansByUserID(tx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if activeLoans >= tier.MaxLoans {
+			return nil, errors.New("loan limit reached for membership tier")
+		}
+		period = tier.LoanPeriod()
+	}
+
+	bookCopy, err := s.findCopy(req.CopyID, req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	switch bookCopy.Status {
+	case model.CopyStatusAvailable:
+	case model.CopyStatusOnHold:
+		// Copies on the hold shelf only go to the member they are set aside for
+		hold, err := s.holdService.GetReadyHold(bookCopy.ID)
+		if err != nil || hold.UserID != user.ID {
+			return nil, errors.New("copy is on hold for another member")
+		}
+	default:
+		return nil, errors.New("copy is not available")
+	}
+
+	now := time.Now()
+	loan := &model.Loan{
+		CopyID:       &bookCopy.ID,
+		BookID:       &bookCopy.BookID,
+		UserID:       user.ID,
+		Barcode:      bookCopy.Barcode,
+		CheckedOutAt: now,
+		DueAt:        now.Add(period),
+		CheckedOutBy: &staffID,
+	}
+
+	// The copy may have been checked out since it was read above
+	created, err := s.loanRepository.CreateLoan(tx, loan, bookCopy.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New("copy is not available")
+	}
+
+	if err := s.holdService.FulfillHoldTx(tx, user.ID, bookCopy.BookID, bookCopy.ID, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetLoan(loan.ID)
+}
+
+// ReturnLoan checks a loan back in. The copy goes to the hold shelf when a
+// member is waiting for the book, otherwise back on the shelf. The return,
+// the hold shelf and the fine are committed together, so a failure leaves
+// the loan open to be returned again and a waiting member's copy is never
+// available at the desk in between.
+func (s *Service) ReturnLoan(id, staffID int) (*model.ReturnResponse, error) {
+	loan, err := s.loanRepository.GetLoan(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+
+	tx, err := s.loanRepository.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	returnedAt := time.Now()
+	returned, err := s.loanRepository.ReturnLoan(tx, id, staffID, returnedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !returned {
+		return nil, errors.New("loan is already returned")
+	}
+
+	holdID := 0
+	if loan.CopyID != nil {
+		holdID, err = s.holdService.ShelveCopyTx(tx, *loan.CopyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	loan.ReturnedAt = &returnedAt
+	fine, err := s.fineService.AssessReturn(tx, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	response := &model.ReturnResponse{}
+	response.Loan, err = s.GetLoan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if holdID != 0 {
+		response.Hold, err = s.holdService.HoldReady(holdID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fine != nil {
+		response.Fine, err = s.fineService.GetFine(fine.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// ReturnCopy checks in the copy with the given barcode, as scanned at the
+// returns desk.
+func (s *Service) ReturnCopy(barcode string, staffID int) (*model.ReturnResponse, error) {
+	bookCopy, err := s.findCopy(0, barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := s.loanRepository.GetActiveLoanByCopyID(bookCopy.ID)
+	if err != nil {
+		return nil, errors.New("copy is not on loan")
+	}
+
+	return s.ReturnLoan(loan.ID, staffID)
+}
+
+func (s *Service) GetLoan(id int) (*model.Loan, error) {
+	loan, err := s.loanRepository.GetLoan(id)
+	if err != nil {
+		return nil, errors.New("loan not found")
+	}
+
+	loan.Overdue = loan.IsOverdue(time.Now())
+	return loan, nil
+}
+
+func (s *Service) GetLoans(params model.LoanQueryParams) (*model.LoanListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	now := time.Now()
+	loans, total, err := s.loanRepository.GetLoans(params, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range loans {
+		loans[i].Overdue = loans[i].IsOverdue(now)
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.LoanListResponse{
+		Loans:      loans,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetUserLoans lists the user's own loan history.
+func (s *Service) GetUserLoans(userID int, params model.LoanQueryParams) (*model.LoanListResponse, error) {
+	params.UserID = userID
+	return s.GetLoans(params)
+}
+
+func (s *Service) findCopy(copyID int, barcode string) (*model.BookCopy, error) {
+	var bookCopy *model.BookCopy
+	var err error
+	if copyID > 0 {
+		bookCopy, err = s.copyRepository.GetCopy(copyID)
+	} else {
+		bookCopy, err = s.copyRepository.GetCopyByBarcode(barcode)
+	}
+	if err != nil {
+		return nil, errors.New("copy not found")
+	}
+	return bookCopy, nil
+}
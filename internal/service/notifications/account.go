@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	"database/sql"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// ExportAccountData adds the user's notifications, followed authors and
+// notification preferences to a personal data export.
+func (s *Service) ExportAccountData(userID int, export *model.AccountExport) error {
+	notifications, err := s.notificationRepository.GetNotificationsByUserID(userID)
+	if err != nil {
+		return err
+	}
+	follows, err := s.notificationRepository.GetFollowsByUserID(userID)
+	if err != nil {
+		return err
+	}
+	preferences, err := s.notificationRepository.GetPreferences(userID)
+	if err != nil {
+		return err
+	}
+
+	export.Notifications = notifications
+	export.FollowedAuthors = follows
+	export.NotificationPreferences = preferences
+	return nil
+}
+
+// CheckAccountDeletion never blocks a deletion.
+func (s *Service) CheckAccountDeletion(tx *sql.Tx, userID int) error {
+	return nil
+}
+
+// DeleteAccountData removes the user's notifications, preferences and
+// follows.
+func (s *Service) DeleteAccountData(tx *sql.Tx, userID int) error {
+	return s.notificationRepository.DeleteUserData(tx, userID)
+}
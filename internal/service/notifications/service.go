@@ -0,0 +1,290 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	notificationRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/notifications"
+	"github.com/ferdy-adr/elibrary-backend/pkg/mailer"
+)
+
+const (
+	// maxEmailAttempts is how often a notification email is tried before it
+	// is marked failed
+	maxEmailAttempts = 5
+	emailBatchSize   = 100
+)
+
+type Service struct {
+	notificationRepository *notificationRepo.Repository
+	mailer                 mailer.Mailer
+}
+
+func NewService(notificationRepository *notificationRepo.Repository, mailer mailer.Mailer) *Service {
+	return &Service{
+		notificationRepository: notificationRepository,
+		mailer:                 mailer,
+	}
+}
+
+// Notify delivers a notification on the channels the user chose for its
+// type. Emails are queued and sent by the notification email job.
+func (s *Service) Notify(userID int, notificationType, title, body string, bookID *int) error {
+	preference, err := s.preference(userID, notificationType)
+	if err != nil {
+		return err
+	}
+	if !preference.InApp && !preference.Email {
+		return nil
+	}
+
+	notification := &model.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body,
+		BookID: bookID,
+		InApp:  preference.InApp,
+	}
+	if preference.Email {
+		status := model.EmailStatusPending
+		notification.EmailStatus = &status
+	}
+
+	return s.notificationRepository.CreateNotification(notification)
+}
+
+func (s *Service) GetNotifications(userID int, params model.NotificationQueryParams) (*model.NotificationListResponse, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	notifications, total, err := s.notificationRepository.GetNotifications(userID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepository.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.NotificationListResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Total:         total,
+		Page:          params.Page,
+		Limit:         params.Limit,
+		TotalPages:    totalPages,
+	}, nil
+}
+
+func (s *Service) MarkRead(id, userID int) (*model.Notification, error) {
+	notification, err := s.notificationRepository.GetNotification(id)
+	if err != nil || notification.UserID != userID || !notification.InApp {
+		return nil, errors.New("notification not found")
+	}
+
+	if notification.ReadAt == nil {
+		if err := s.notificationRepository.MarkRead(id, time.Now()); err != nil {
+			return nil, err
+		}
+		return s.notificationRepository.GetNotification(id)
+	}
+
+	return notification, nil
+}
+
+// MarkAllRead marks the user's whole inbox as read.
+func (s *Service) MarkAllRead(userID int) error {
+	return s.notificationRepository.MarkAllRead(userID, time.Now())
+}
+
+// GetPreferences returns the channels of every notification type, falling
+// back to the type's defaults where the user has not set any.
+func (s *Service) GetPreferences(userID int) ([]model.NotificationPreference, error) {
+	saved, err := s.notificationRepository.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]model.NotificationPreference, len(saved))
+	for _, preference := range saved {
+		byType[preference.Type] = preference
+	}
+
+	preferences := make([]model.NotificationPreference, len(model.NotificationTypes))
+	for i, defaults := range model.NotificationTypes {
+		preferences[i] = defaults
+		if preference, ok := byType[defaults.Type]; ok {
+			preferences[i] = preference
+		}
+	}
+	return preferences, nil
+}
+
+func (s *Service) UpdatePreference(userID int, notificationType string, req model.UpdateNotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	if req.InApp == nil && req.Email == nil {
+		return nil, errors.New("no fields to update")
+	}
+
+	preference, err := s.preference(userID, notificationType)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.InApp != nil {
+		preference.InApp = *req.InApp
+	}
+	if req.Email != nil {
+		preference.Email = *req.Email
+	}
+
+	if err := s.notificationRepository.UpsertPreference(userID, *preference); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+func (s *Service) preference(userID int, notificationType string) (*model.NotificationPreference, error) {
+	preferences, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Type == notificationType {
+			return &preference, nil
+		}
+	}
+	return nil, errors.New("unknown notification type")
+}
+
+func (s *Service) FollowAuthor(userID int, req model.FollowAuthorRequest) (*model.AuthorFollow, error) {
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		return nil, errors.New("author is required")
+	}
+
+	exists, err := s.notificationRepository.CheckFollowExists(userID, author)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("author already followed")
+	}
+
+	follow := &model.AuthorFollow{
+		UserID:    userID,
+		Author:    author,
+		CreatedAt: time.Now(),
+	}
+	if err := s.notificationRepository.CreateFollow(follow); err != nil {
+		return nil, err
+	}
+	return follow, nil
+}
+
+func (s *Service) GetFollows(userID int) ([]model.AuthorFollow, error) {
+	return s.notificationRepository.GetFollowsByUserID(userID)
+}
+
+func (s *Service) UnfollowAuthor(id, userID int) error {
+	deleted, err := s.notificationRepository.DeleteFollow(id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("follow not found")
+	}
+	return nil
+}
+
+// HandleBookEvent notifies the followers of a new book's author. Register
+// it with the books service.
+func (s *Service) HandleBookEvent(event model.BookEvent) {
+	if event.Type != model.BookEventCreated {
+		return
+	}
+
+	go s.notifyFollowers(event.Book)
+}
+
+func (s *Service) notifyFollowers(book model.Book) {
+	userIDs, err := s.notificationRepository.GetFollowerIDsByAuthor(book.Author)
+	if err != nil {
+		log.Printf("Failed to get followers of %s: %v", book.Author, err)
+		return
+	}
+
+	title := fmt.Sprintf("New book by %s", book.Author)
+	body := fmt.Sprintf("%s by %s (%d) has been added to the catalog.", book.Title, book.Author, book.Year)
+	for _, userID := range userIDs {
+		if err := s.Notify(userID, model.NotificationTypeNewBook, title, body, &book.ID); err != nil {
+			log.Printf("Failed to notify user %d of book %d: %v", userID, book.ID, err)
+		}
+	}
+}
+
+// SendPendingEmails sends queued notification emails. Failed sends are
+// retried on later runs up to maxEmailAttempts times.
+func (s *Service) SendPendingEmails() (string, error) {
+	emails, err := s.notificationRepository.GetPendingEmails(emailBatchSize)
+	if err != nil {
+		return "", err
+	}
+
+	sent, failed := 0, 0
+	for _, email := range emails {
+		// Deleted accounts have no real address left
+		if email.Deleted {
+			if err := s.notificationRepository.RecordEmailAttempt(email.ID, model.EmailStatusFailed, nil); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		err := s.mailer.Send(mailer.Message{
+			To:      email.Email,
+			Subject: email.Title,
+			Body:    fmt.Sprintf("Hi %s,\n\n%s\n", email.FullName, email.Body),
+		})
+		if err != nil {
+			log.Printf("Failed to email notification %d: %v", email.ID, err)
+			failed++
+
+			status := model.EmailStatusPending
+			if email.EmailAttempts+1 >= maxEmailAttempts {
+				status = model.EmailStatusFailed
+			}
+			if err := s.notificationRepository.RecordEmailAttempt(email.ID, status, nil); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		sent++
+		now := time.Now()
+		if err := s.notificationRepository.RecordEmailAttempt(email.ID, model.EmailStatusSent, &now); err != nil {
+			return "", err
+		}
+	}
+
+	result := fmt.Sprintf("%d emails sent", sent)
+	if failed > 0 {
+		return result, fmt.Errorf("%d emails could not be sent", failed)
+	}
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package shelves
+
+import (
+	"database/sql"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+)
+
+// ExportAccountData adds the user's shelves, private ones included, and
+// the books on them to a personal data export.
+func (s *Service) ExportAccountData(userID int, export *model.AccountExport) error {
+	shelves, err := s.shelfRepository.GetShelvesByUserID(userID, false)
+	if err != nil {
+		return err
+	}
+	shelfBooks, err := s.shelfRepository.GetShelfBooksByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	export.Shelves = shelves
+	export.ShelfBooks = shelfBooks
+	return nil
+}
+
+// CheckAccountDeletion never blocks a deletion.
+func (s *Service) CheckAccountDeletion(tx *sql.Tx, userID int) error {
+	return nil
+}
+
+// DeleteAccountData removes the user's shelves.
+func (s *Service) DeleteAccountData(tx *sql.Tx, userID int) error {
+	return s.shelfRepository.DeleteUserShelves(tx, userID)
+}
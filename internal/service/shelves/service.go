@@ -0,0 +1,258 @@
+package shelves
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	bookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/books"
+	shelfRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/shelves"
+)
+
+type Service struct {
+	shelfRepository *shelfRepo.Repository
+	bookRepository  *bookRepo.Repository
+}
+
+func NewService(shelfRepository *shelfRepo.Repository, bookRepository *bookRepo.Repository) *Service {
+	return &Service{
+		shelfRepository: shelfRepository,
+		bookRepository:  bookRepository,
+	}
+}
+
+// GetShelves lists the user's own shelves, creating the reading status
+// shelves on first use.
+func (s *Service) GetShelves(userID int) ([]model.Shelf, error) {
+	if err := s.shelfRepository.EnsureReadingStatusShelves(userID); err != nil {
+		return nil, err
+	}
+	return s.shelfRepository.GetShelvesByUserID(userID, false)
+}
+
+// GetUserShelves lists another user's public shelves, or all of them when
+// viewers look at their own.
+func (s *Service) GetUserShelves(ownerID, viewerID int) ([]model.Shelf, error) {
+	if ownerID == viewerID {
+		return s.GetShelves(ownerID)
+	}
+	return s.shelfRepository.GetShelvesByUserID(ownerID, true)
+}
+
+// GetShelf returns a shelf the viewer may see: a public shelf or one of
+// their own. viewerID is 0 for anonymous callers.
+func (s *Service) GetShelf(id, viewerID int) (*model.Shelf, error) {
+	shelf, err := s.shelfRepository.GetShelf(id)
+	if err != nil || (!shelf.IsPublic && shelf.UserID != viewerID) {
+		return nil, errors.New("shelf not found")
+	}
+	return shelf, nil
+}
+
+// ownShelf returns a shelf the user may change. Other users' shelves are
+// reported as missing whether or not they are public.
+func (s *Service) ownShelf(id, userID int) (*model.Shelf, error) {
+	shelf, err := s.shelfRepository.GetShelf(id)
+	if err != nil || shelf.UserID != userID {
+		return nil, errors.New("shelf not found")
+	}
+	return shelf, nil
+}
+
+func (s *Service) checkName(userID int, name string, excludeID int) error {
+	for _, shelf := range model.ReadingStatusShelves {
+		if strings.EqualFold(name, shelf.Name) {
+			return errors.New("shelf name is reserved")
+		}
+	}
+
+	exists, err := s.shelfRepository.CheckNameExists(userID, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("shelf name already exists")
+	}
+	return nil
+}
+
+func (s *Service) CreateShelf(userID int, req model.CreateShelfRequest) (*model.Shelf, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("shelf name is required")
+	}
+	if err := s.checkName(userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	shelf := &model.Shelf{
+		UserID:      userID,
+		Name:        name,
+		Description: req.Description,
+		Kind:        model.ShelfKindCustom,
+		IsPublic:    req.IsPublic,
+	}
+	if err := s.shelfRepository.CreateShelf(shelf); err != nil {
+		return nil, err
+	}
+
+	return s.shelfRepository.GetShelf(shelf.ID)
+}
+
+func (s *Service) UpdateShelf(id, userID int, req model.UpdateShelfRequest) (*model.Shelf, error) {
+	shelf, err := s.ownShelf(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name != "" && req.Name != shelf.Name {
+		if shelf.IsReadingStatus() {
+			return nil, errors.New("reading status shelves cannot be renamed")
+		}
+		if err := s.checkName(userID, req.Name, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.shelfRepository.UpdateShelf(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.shelfRepository.GetShelf(id)
+}
+
+func (s *Service) DeleteShelf(id, userID int) error {
+	shelf, err := s.ownShelf(id, userID)
+	if err != nil {
+		return err
+	}
+	if shelf.IsReadingStatus() {
+		return errors.New("reading status shelves cannot be deleted")
+	}
+
+	return s.shelfRepository.DeleteShelf(id)
+}
+
+func (s *Service) GetShelfBooks(id, viewerID int, params model.ShelfBookQueryParams) (*model.ShelfBookListResponse, error) {
+	shelf, err := s.GetShelf(id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	books, total, err := s.shelfRepository.GetShelfBooks(id, params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.ShelfBookListResponse{
+		Shelf:      *shelf,
+		Books:      books,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// AddBook puts a book on one of the user's shelves. Putting it on a
+// reading status shelf takes it off the other two.
+func (s *Service) AddBook(id, userID int, req model.AddShelfBookRequest) (*model.Shelf, error) {
+	shelf, err := s.ownShelf(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bookRepository.GetBookByID(req.BookID); err != nil {
+		return nil, errors.New("book not found")
+	}
+
+	onShelf, err := s.shelfRepository.CheckBookOnShelf(id, req.BookID)
+	if err != nil {
+		return nil, err
+	}
+	if onShelf {
+		return nil, errors.New("book is already on this shelf")
+	}
+
+	removeFrom := []int{}
+	if shelf.IsReadingStatus() {
+		shelves, err := s.shelfRepository.GetShelvesByUserID(userID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range shelves {
+			if other.IsReadingStatus() && other.ID != id {
+				removeFrom = append(removeFrom, other.ID)
+			}
+		}
+	}
+
+	if err := s.shelfRepository.AddBook(id, req.BookID, req.Position, removeFrom); err != nil {
+		return nil, err
+	}
+
+	return s.shelfRepository.GetShelf(id)
+}
+
+func (s *Service) RemoveBook(id, userID, bookID int) error {
+	if _, err := s.ownShelf(id, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.shelfRepository.RemoveBook(id, bookID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("book is not on this shelf")
+	}
+	return nil
+}
+
+// ReorderBooks sets the order of the shelf. The request must list every
+// book on the shelf exactly once.
+func (s *Service) ReorderBooks(id, userID int, req model.ReorderShelfRequest) error {
+	if _, err := s.ownShelf(id, userID); err != nil {
+		return err
+	}
+
+	current, err := s.shelfRepository.GetShelfBookIDs(id)
+	if err != nil {
+		return err
+	}
+
+	onShelf := make(map[int]bool, len(current))
+	for _, bookID := range current {
+		onShelf[bookID] = true
+	}
+
+	if len(req.BookIDs) != len(current) {
+		return errors.New("book_ids must list every book on the shelf exactly once")
+	}
+	for _, bookID := range req.BookIDs {
+		if !onShelf[bookID] {
+			return errors.New("book_ids must list every book on the shelf exactly once")
+		}
+		delete(onShelf, bookID)
+	}
+
+	return s.shelfRepository.ReorderBooks(id, req.BookIDs)
+}
+
+// GetBookShelves returns the user's shelves that hold the book.
+func (s *Service) GetBookShelves(userID, bookID int) ([]model.ShelfRef, error) {
+	return s.shelfRepository.GetShelvesContainingBook(userID, bookID)
+}
@@ -0,0 +1,159 @@
+package tiers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	tierRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/tiers"
+	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
+)
+
+type Service struct {
+	tierRepository *tierRepo.Repository
+	userRepository *userRepo.Repository
+}
+
+func NewService(tierRepository *tierRepo.Repository, userRepository *userRepo.Repository) *Service {
+	return &Service{
+		tierRepository: tierRepository,
+		userRepository: userRepository,
+	}
+}
+
+func (s *Service) GetTiers() ([]model.MembershipTier, error) {
+	return s.tierRepository.GetTiers()
+}
+
+func (s *Service) GetTier(id int) (*model.MembershipTier, error) {
+	tier, err := s.tierRepository.GetTier(id)
+	if err != nil {
+		return nil, errors.New("tier not found")
+	}
+	return tier, nil
+}
+
+func (s *Service) CreateTier(req model.CreateMembershipTierRequest) (*model.MembershipTier, error) {
+	exists, err := s.tierRepository.CheckNameExists(req.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("tier name already exists")
+	}
+
+	tier := &model.MembershipTier{
+		Name:           req.Name,
+		Description:    req.Description,
+		MaxLoans:       req.MaxLoans,
+		LoanPeriodDays: req.LoanPeriodDays,
+		HoldLimit:      req.HoldLimit,
+		IsDefault:      req.IsDefault,
+	}
+	err = s.tierRepository.CreateTier(tier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tierRepository.GetTier(tier.ID)
+}
+
+func (s *Service) UpdateTier(id int, req model.UpdateMembershipTierRequest) (*model.MembershipTier, error) {
+	existingTier, err := s.GetTier(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" && req.Name != existingTier.Name {
+		exists, err := s.tierRepository.CheckNameExists(req.Name, id)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("tier name already exists")
+		}
+	}
+
+	tier := &model.MembershipTier{
+		Name:           req.Name,
+		MaxLoans:       req.MaxLoans,
+		LoanPeriodDays: req.LoanPeriodDays,
+		IsDefault:      req.IsDefault,
+	}
+	if req.Description != nil {
+		tier.Description = *req.Description
+	}
+	if req.HoldLimit != nil {
+		tier.HoldLimit = *req.HoldLimit
+	}
+
+	err = s.tierRepository.UpdateTier(id, tier, req.Description != nil, req.HoldLimit != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tierRepository.GetTier(id)
+}
+
+// DeleteTier removes a tier nobody is assigned to. The default tier has to
+// be replaced by another default first.
+func (s *Service) DeleteTier(id int) error {
+	tier, err := s.GetTier(id)
+	if err != nil {
+		return err
+	}
+	if tier.IsDefault {
+		return errors.New("cannot delete the default tier")
+	}
+
+	users, err := s.tierRepository.CountUsersByTierID(id)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return errors.New("tier is assigned to users")
+	}
+
+	return s.tierRepository.DeleteTier(id)
+}
+
+// AssignTier sets the user's tier; a nil tier ID moves them to the default.
+func (s *Service) AssignTier(userID int, req model.UpdateUserTierRequest) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil || user.IsDeleted() {
+		return nil, errors.New("user not found")
+	}
+
+	if req.TierID != nil {
+		if _, err := s.GetTier(*req.TierID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.userRepository.UpdateUserTier(userID, req.TierID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(userID)
+}
+
+// GetUserTier returns the tier whose limits apply to the user. It returns
+// nil when the user has no tier and no default tier is set, in which case
+// no tier limits apply.
+func (s *Service) GetUserTier(userID int) (*model.MembershipTier, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TierID != nil {
+		return s.tierRepository.GetTier(*user.TierID)
+	}
+
+	tier, err := s.tierRepository.GetDefaultTier()
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return tier, err
+}
@@ -0,0 +1,217 @@
+package users
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	authEventRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/authevents"
+	lockoutRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/lockouts"
+	sessionRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/sessions"
+	userRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/users"
+)
+
+type Service struct {
+	userRepository      *userRepo.Repository
+	lockoutRepository   *lockoutRepo.Repository
+	sessionRepository   *sessionRepo.Repository
+	authEventRepository *authEventRepo.Repository
+}
+
+func NewService(
+	userRepository *userRepo.Repository,
+	lockoutRepository *lockoutRepo.Repository,
+	sessionRepository *sessionRepo.Repository,
+	authEventRepository *authEventRepo.Repository,
+) *Service {
+	return &Service{
+		userRepository:      userRepository,
+		lockoutRepository:   lockoutRepository,
+		sessionRepository:   sessionRepository,
+		authEventRepository: authEventRepository,
+	}
+}
+
+func (s *Service) GetUsers(params model.UserQueryParams) (*model.UserListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	users, total, err := s.userRepository.GetUsers(params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.UserListResponse{
+		Users:      users,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *Service) GetUser(id int) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// DeactivateUser blocks the account and ends its sessions. Outstanding access
+// tokens are rejected by the auth middleware from now on.
+func (s *Service) DeactivateUser(id int, adminID int) (*model.User, error) {
+	if id == adminID {
+		return nil, errors.New("cannot deactivate your own account")
+	}
+
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsActive() {
+		return nil, errors.New("user is already deactivated")
+	}
+
+	if user.Role == model.RoleAdmin {
+		admins, err := s.userRepository.CountActiveUsersByRole(model.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, errors.New("cannot deactivate the last admin")
+		}
+	}
+
+	err = s.userRepository.DeactivateUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionRepository.RevokeUserSessions(id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(id)
+}
+
+func (s *Service) ReactivateUser(id int) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.IsDeleted() {
+		return nil, errors.New("user has been deleted")
+	}
+	if user.IsActive() {
+		return nil, errors.New("user is not deactivated")
+	}
+
+	err = s.userRepository.ReactivateUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(id)
+}
+
+func (s *Service) UpdateRole(id int, req model.UpdateRoleRequest) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	// Never leave the system without an active admin
+	if user.Role == model.RoleAdmin && user.IsActive() && req.Role != model.RoleAdmin {
+		admins, err := s.userRepository.CountActiveUsersByRole(model.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, errors.New("cannot remove the last admin")
+		}
+	}
+
+	err = s.userRepository.UpdateUserRole(id, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(id)
+}
+
+func (s *Service) GetLockouts(params model.LockoutQueryParams) (*model.LockoutListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	lockouts, total, err := s.lockoutRepository.GetLockouts(params, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.LockoutListResponse{
+		Lockouts:   lockouts,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *Service) UnlockUser(id int, adminID int) error {
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.lockoutRepository.UnlockUsername(user.Username, adminID)
+}
+
+func (s *Service) GetAuthEvents(params model.AuthEventQueryParams) (*model.AuthEventListResponse, error) {
+	// Set default values
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 50
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	events, total, err := s.authEventRepository.GetEvents(params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.AuthEventListResponse{
+		Events:     events,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
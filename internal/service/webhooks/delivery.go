@@ -0,0 +1,181 @@
+package webhooks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/configs"
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	"github.com/ferdy-adr/elibrary-backend/pkg/textutil"
+)
+
+const (
+	defaultDeliveryTimeout = 10 * time.Second
+	defaultMaxAttempts     = 8
+	defaultRetryBaseDelay  = 30 * time.Second
+	defaultMaxRetryDelay   = 6 * time.Hour
+
+	// maxLogLength fits the response_body and last_error columns
+	maxLogLength  = 1024
+	retryBatchLen = 100
+)
+
+func deliveryTimeout() time.Duration {
+	if timeout := configs.Get().Webhooks.Timeout; timeout > 0 {
+		return timeout
+	}
+	return defaultDeliveryTimeout
+}
+
+func maxAttempts() int {
+	if attempts := configs.Get().Webhooks.MaxAttempts; attempts > 0 {
+		return attempts
+	}
+	return defaultMaxAttempts
+}
+
+// retryDelay is the wait after the given number of failed attempts:
+// the base delay, doubled for every attempt after the first, up to the max.
+func retryDelay(attempts int) time.Duration {
+	base := configs.Get().Webhooks.RetryBaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	max := configs.Get().Webhooks.MaxRetryDelay
+	if max <= 0 {
+		max = defaultMaxRetryDelay
+	}
+
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// RetryDueDeliveries sends pending deliveries whose retry is due. It runs
+// as a scheduled job.
+func (s *Service) RetryDueDeliveries() (string, error) {
+	deliveries, err := s.webhookRepository.GetDueDeliveries(time.Now(), retryBatchLen)
+	if err != nil {
+		return "", err
+	}
+
+	succeeded, retrying, failed := s.sendAll(deliveries)
+	return fmt.Sprintf("%d delivered, %d to retry, %d failed", succeeded, retrying, failed), nil
+}
+
+// sendAll attempts each delivery once and counts the outcomes.
+func (s *Service) sendAll(deliveries []model.WebhookDelivery) (succeeded, retrying, failed int) {
+	for i := range deliveries {
+		status, err := s.attempt(&deliveries[i])
+		if err != nil {
+			log.Printf("Failed to send webhook delivery %d: %v", deliveries[i].ID, err)
+			continue
+		}
+
+		switch status {
+		case model.DeliveryStatusSucceeded:
+			succeeded++
+		case model.DeliveryStatusPending:
+			retrying++
+		case model.DeliveryStatusFailed:
+			failed++
+		}
+	}
+	return succeeded, retrying, failed
+}
+
+// attempt claims the delivery, posts it and records the outcome. It returns
+// the new status, or "" when another worker already had the delivery.
+func (s *Service) attempt(delivery *model.WebhookDelivery) (string, error) {
+	now := time.Now()
+	claimed, err := s.webhookRepository.ClaimDelivery(delivery.ID, now, now.Add(deliveryTimeout()+time.Minute))
+	if err != nil || !claimed {
+		return "", err
+	}
+
+	webhook, err := s.webhookRepository.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		return "", err
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	delivery.ResponseBody = ""
+	delivery.LastError = ""
+
+	if webhook.IsActive {
+		s.post(webhook, delivery)
+	} else {
+		delivery.LastError = "webhook is disabled"
+	}
+
+	switch {
+	case delivery.LastError == "":
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case webhook.IsActive && delivery.Attempts < maxAttempts():
+		delivery.Status = model.DeliveryStatusPending
+		next := now.Add(retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	default:
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+	}
+
+	if err := s.webhookRepository.RecordAttempt(delivery); err != nil {
+		return "", err
+	}
+	return delivery.Status, nil
+}
+
+// post sends the signed payload and fills in the response, setting
+// LastError when the endpoint did not answer with a 2xx status.
+func (s *Service) post(webhook *model.Webhook, delivery *model.WebhookDelivery) {
+	secret, err := decryptSecret(webhook.Secret)
+	if err != nil {
+		delivery.LastError = err.Error()
+		return
+	}
+
+	payload := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.LastError = textutil.Truncate(err.Error(), maxLogLength)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "eLibrary-Webhooks/1.0")
+	req.Header.Set("X-Elibrary-Event", delivery.EventType)
+	req.Header.Set("X-Elibrary-Delivery", strconv.Itoa(delivery.ID))
+	req.Header.Set("X-Elibrary-Timestamp", timestamp)
+	req.Header.Set("X-Elibrary-Signature", sign(secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.LastError = textutil.Truncate(err.Error(), maxLogLength)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxLogLength))
+	delivery.ResponseStatus = &resp.StatusCode
+	delivery.ResponseBody = textutil.Truncate(string(body), maxLogLength)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		delivery.LastError = textutil.Truncate("endpoint responded with "+resp.Status, maxLogLength)
+	}
+}
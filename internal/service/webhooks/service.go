@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ferdy-adr/elibrary-backend/internal/model"
+	webhookRepo "github.com/ferdy-adr/elibrary-backend/internal/repository/webhooks"
+)
+
+type Service struct {
+	webhookRepository *webhookRepo.Repository
+	client            *http.Client
+}
+
+func NewService(webhookRepository *webhookRepo.Repository) *Service {
+	return &Service{
+		webhookRepository: webhookRepository,
+		client: &http.Client{
+			Timeout: deliveryTimeout(),
+			// A redirect would turn the POST into a GET; report it instead
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an http or https URL")
+	}
+	return nil
+}
+
+// CreateWebhook registers an endpoint for the given events. The response
+// carries the signing secret, which cannot be retrieved later.
+func (s *Service) CreateWebhook(req model.CreateWebhookRequest, createdBy int) (*model.CreateWebhookResponse, error) {
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      req.Events,
+		Secret:      encrypted,
+		IsActive:    true,
+		CreatedBy:   &createdBy,
+	}
+	if err := s.webhookRepository.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	created, err := s.webhookRepository.GetWebhook(webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CreateWebhookResponse{
+		Webhook: *created,
+		Secret:  secret,
+	}, nil
+}
+
+func (s *Service) GetWebhooks() ([]model.Webhook, error) {
+	return s.webhookRepository.GetWebhooks()
+}
+
+func (s *Service) GetWebhook(id int) (*model.Webhook, error) {
+	webhook, err := s.webhookRepository.GetWebhook(id)
+	if err != nil {
+		return nil, errors.New("webhook not found")
+	}
+	return webhook, nil
+}
+
+func (s *Service) UpdateWebhook(id int, req model.UpdateWebhookRequest) (*model.Webhook, error) {
+	if _, err := s.GetWebhook(id); err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		if err := validateURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.webhookRepository.UpdateWebhook(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepository.GetWebhook(id)
+}
+
+func (s *Service) DeleteWebhook(id int) error {
+	if _, err := s.GetWebhook(id); err != nil {
+		return err
+	}
+	return s.webhookRepository.DeleteWebhook(id)
+}
+
+func (s *Service) GetDeliveries(webhookID int, params model.WebhookDeliveryQueryParams) (*model.WebhookDeliveryListResponse, error) {
+	if _, err := s.GetWebhook(webhookID); err != nil {
+		return nil, err
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	deliveries, total, err := s.webhookRepository.GetDeliveries(webhookID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (total + params.Limit - 1) / params.Limit
+
+	return &model.WebhookDeliveryListResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// HandleBookEvent queues a delivery of the event for every active webhook
+// subscribed to it and sends them in the background. Register it with the
+// books service.
+func (s *Service) HandleBookEvent(event model.BookEvent) {
+	webhooks, err := s.webhookRepository.GetActiveWebhooks()
+	if err != nil {
+		log.Printf("Failed to get webhooks for %s: %v", event.Type, err)
+		return
+	}
+
+	eventID, err := generateEventID()
+	if err != nil {
+		log.Printf("Failed to queue %s webhooks: %v", event.Type, err)
+		return
+	}
+
+	payload, err := json.Marshal(model.WebhookPayload{
+		ID:         eventID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       model.WebhookEventData{Book: event.Book},
+	})
+	if err != nil {
+		log.Printf("Failed to queue %s webhooks: %v", event.Type, err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := []model.WebhookDelivery{}
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.Type) {
+			continue
+		}
+
+		delivery := model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		}
+		if err := s.webhookRepository.CreateDelivery(&delivery); err != nil {
+			log.Printf("Failed to queue %s for webhook %d: %v", event.Type, webhook.ID, err)
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if len(deliveries) > 0 {
+		go s.sendAll(deliveries)
+	}
+}
+
+// ReplayDelivery sends a failed delivery again as a new delivery with the
+// same event ID and payload.
+func (s *Service) ReplayDelivery(id int) (*model.WebhookDelivery, error) {
+	delivery, err := s.webhookRepository.GetDelivery(id)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+	if delivery.Status != model.DeliveryStatusFailed {
+		return nil, errors.New("only failed deliveries can be replayed")
+	}
+
+	replayed, err := s.webhookRepository.HasReplay(id)
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		return nil, errors.New("delivery was already replayed")
+	}
+
+	replay, err := s.queueReplay(delivery)
+	if err != nil {
+		return nil, err
+	}
+	if replay == nil {
+		return nil, errors.New("delivery was already replayed")
+	}
+
+	go s.sendAll([]model.WebhookDelivery{*replay})
+	return replay, nil
+}
+
+// ReplayFailedDeliveries replays every failed delivery of a webhook that
+// was not replayed yet. A replay that failed in turn is replayed in place
+// of its original.
+func (s *Service) ReplayFailedDeliveries(webhookID int) ([]model.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(webhookID); err != nil {
+		return nil, err
+	}
+
+	failed, err := s.webhookRepository.GetFailedDeliveries(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	replays := []model.WebhookDelivery{}
+	for i := range failed {
+		replay, err := s.queueReplay(&failed[i])
+		if err != nil {
+			return nil, err
+		}
+		if replay != nil {
+			replays = append(replays, *replay)
+		}
+	}
+
+	if len(replays) > 0 {
+		go s.sendAll(replays)
+	}
+	return replays, nil
+}
+
+func (s *Service) queueReplay(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	now := time.Now()
+	replay := &model.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &now,
+		ReplayOf:      &delivery.ID,
+	}
+	// Returns nil when a concurrent request replayed the delivery first
+	created, err := s.webhookRepository.CreateReplay(replay)
+	if err != nil || !created {
+		return nil, err
+	}
+
+	return s.webhookRepository.GetDelivery(replay.ID)
+}
@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"@fortnightly",
+	}
+
+	for _, expr := range tests {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		expr   string
+		minute []int
+	}{
+		{"0 * * * *", []int{0}},
+		{"0,30 * * * *", []int{0, 30}},
+		{"10-13 * * * *", []int{10, 11, 12, 13}},
+		{"*/20 * * * *", []int{0, 20, 40}},
+		{"5/20 * * * *", []int{5, 25, 45}},
+		{"10-30/10,59 * * * *", []int{10, 20, 30, 59}},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+
+		var want uint64
+		for _, v := range tt.minute {
+			want |= 1 << uint(v)
+		}
+		if s.minute != want {
+			t.Errorf("Parse(%q) minutes = %b, want %b", tt.expr, s.minute, want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every 15 minutes", "*/15 * * * *", at(2026, 1, 15, 10, 7), at(2026, 1, 15, 10, 15)},
+		{"strictly after from", "0 8 * * *", at(2026, 1, 15, 8, 0), at(2026, 1, 16, 8, 0)},
+		{"seconds are dropped", "@hourly", at(2026, 1, 15, 10, 59).Add(30 * time.Second), at(2026, 1, 15, 11, 0)},
+		{"step with start", "5/20 * * * *", at(2026, 1, 15, 10, 6), at(2026, 1, 15, 10, 25)},
+		{"list of days", "0 12 1,15 * *", at(2026, 1, 15, 12, 0), at(2026, 2, 1, 12, 0)},
+		{"month without the day", "30 23 31 * *", at(2026, 4, 1, 0, 0), at(2026, 5, 31, 23, 30)},
+		{"year rollover", "0 0 1 1 *", at(2026, 12, 31, 23, 59), at(2027, 1, 1, 0, 0)},
+		{"hour rollover at midnight", "15 * * * *", at(2026, 1, 31, 23, 20), at(2026, 2, 1, 0, 15)},
+		{"weekdays skip the weekend", "0 9 * * 1-5", at(2026, 1, 16, 10, 0), at(2026, 1, 19, 9, 0)},
+		{"sunday as 0", "0 0 * * 0", at(2026, 1, 15, 0, 0), at(2026, 1, 18, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", at(2026, 1, 15, 0, 0), at(2026, 1, 18, 0, 0)},
+		{"day of month or week, month day first", "0 0 13 * 5", at(2026, 1, 10, 0, 0), at(2026, 1, 13, 0, 0)},
+		{"day of month or week, weekday first", "0 0 13 * 5", at(2026, 1, 14, 0, 0), at(2026, 1, 16, 0, 0)},
+		{"day of month with month restriction", "0 6 1 3,6 *", at(2026, 3, 1, 6, 0), at(2026, 6, 1, 6, 0)},
+		{"weekday with month restriction", "0 0 * 2 1", at(2026, 1, 15, 0, 0), at(2026, 2, 2, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(2026, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"never", "0 0 30 2 *", at(2026, 1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestNextKeepsLocation(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	s, err := Parse("0 8 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.Next(time.Date(2026, 1, 15, 9, 0, 0, 0, jakarta))
+	want := time.Date(2026, 1, 16, 8, 0, 0, 0, jakarta)
+	if !got.Equal(want) || got.Location() != jakarta {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+}
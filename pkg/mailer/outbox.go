@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// OutboxMailer writes every message to a .eml file instead of sending it,
+// which is useful for local development and tests.
+type OutboxMailer struct {
+	dir   string
+	from  string
+	count atomic.Uint64
+}
+
+func NewOutboxMailer(dir, from string) *OutboxMailer {
+	return &OutboxMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m *OutboxMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%d_%d.eml", time.Now().UnixNano(), m.count.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, filename), msg.build(m.from), 0644)
+}
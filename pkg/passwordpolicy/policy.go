@@ -0,0 +1,137 @@
+// Package passwordpolicy checks new passwords against length and character
+// class rules, a list of breached passwords and the user's own details.
+package passwordpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// bcrypt ignores everything after 72 bytes, so longer passwords are refused
+// rather than silently truncated.
+const maxBytes = 72
+
+//go:embed breached.txt
+var bundledList string
+
+type Policy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	CheckBreached    bool
+
+	breached map[string]struct{}
+}
+
+// New returns a policy using the bundled breached password list.
+func New(policy Policy) *Policy {
+	policy.breached = map[string]struct{}{}
+	policy.addList(strings.NewReader(bundledList))
+	return &policy
+}
+
+// LoadList adds the passwords in the file, one per line, to the breached list.
+func (p *Policy) LoadList(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return p.addList(f)
+}
+
+func (p *Policy) addList(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Validate returns every rule the password breaks, or nil when it is
+// acceptable. personal holds values the password must not match or contain,
+// such as the username and email address.
+func (p *Policy) Validate(password string, personal ...string) []string {
+	var violations []string
+
+	if n := len([]rune(password)); n < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if len(password) > maxBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", maxBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+	if p.RequireUppercase && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	lower := strings.ToLower(password)
+	if p.CheckBreached {
+		if _, found := p.breached[lower]; found {
+			violations = append(violations, "is too common and appears in known data breaches")
+		}
+	}
+
+	for _, value := range personalTerms(personal) {
+		if strings.Contains(lower, value) {
+			violations = append(violations, "must not contain your username or email")
+			break
+		}
+	}
+
+	return violations
+}
+
+// personalTerms expands the personal values into lowercase terms, adding the
+// local part of email addresses. Very short terms are skipped because they
+// would reject too many unrelated passwords.
+func personalTerms(values []string) []string {
+	var terms []string
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		candidates := []string{value}
+		if at := strings.Index(value, "@"); at > 0 {
+			candidates = append(candidates, value[:at])
+		}
+		for _, term := range candidates {
+			if len(term) >= 3 {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
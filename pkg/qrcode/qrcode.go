@@ -0,0 +1,461 @@
+// Package qrcode encodes short strings as QR codes (ISO/IEC 18004) in byte
+// mode with error correction level M. Versions 1 to 10 are supported, which
+// is plenty for otpauth:// URIs.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+const quietZone = 4
+
+// blockLayout describes the error correction blocks of a version at level M.
+type blockLayout struct {
+	ecPerBlock  int
+	group1      int
+	group1Data  int
+	group2      int
+	group2Data  int
+	alignCenter []int
+}
+
+var layouts = [...]blockLayout{
+	1:  {10, 1, 16, 0, 0, nil},
+	2:  {16, 1, 28, 0, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, 39, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, 37, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+func (l blockLayout) dataCodewords() int {
+	return l.group1*l.group1Data + l.group2*l.group2Data
+}
+
+// Code is an encoded QR symbol. Modules[y][x] is true for dark modules.
+type Code struct {
+	Size    int
+	Modules [][]bool
+
+	function [][]bool
+}
+
+// Encode builds the smallest QR code that holds content.
+func Encode(content string) (*Code, error) {
+	data := []byte(content)
+
+	version := 0
+	for v := 1; v < len(layouts); v++ {
+		if len(data)+charCountBits(v)/8+1 <= layouts[v].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("qrcode: content too long")
+	}
+
+	codewords := addErrorCorrection(encodeData(data, version), layouts[version])
+
+	size := version*4 + 17
+	code := &Code{
+		Size:     size,
+		Modules:  makeGrid(size),
+		function: makeGrid(size),
+	}
+	code.drawFunctionPatterns(version)
+	code.drawCodewords(codewords)
+
+	// Pick the mask with the lowest penalty score
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		code.applyMask(mask)
+		code.drawFormatBits(mask)
+		if penalty := code.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		code.applyMask(mask)
+	}
+	code.applyMask(bestMask)
+	code.drawFormatBits(bestMask)
+
+	return code, nil
+}
+
+// PNG renders the code with a quiet zone, using scale pixels per module.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+
+	dim := (c.Size + 2*quietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	for y := 0; y < dim; y++ {
+		for x := 0; x < dim; x++ {
+			mx, my := x/scale-quietZone, y/scale-quietZone
+			dark := mx >= 0 && my >= 0 && mx < c.Size && my < c.Size && c.Modules[my][mx]
+			if dark {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PNG is a shortcut for Encode followed by Code.PNG.
+func PNG(content string, scale int) ([]byte, error) {
+	code, err := Encode(content)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(scale)
+}
+
+func makeGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func charCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData builds the byte-mode bit stream padded to the data capacity.
+func encodeData(data []byte, version int) []byte {
+	capacity := layouts[version].dataCodewords()
+	var bits bitBuffer
+
+	bits.append(0x4, 4) // byte mode
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// Terminator, then pad to a byte boundary
+	terminator := capacity*8 - bits.len()
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	if rem := bits.len() % 8; rem != 0 {
+		bits.append(0, 8-rem)
+	}
+
+	out := bits.bytes()
+	for pad := byte(0xEC); len(out) < capacity; pad ^= 0xEC ^ 0x11 {
+		out = append(out, pad)
+	}
+	return out
+}
+
+// addErrorCorrection splits data into blocks, appends Reed-Solomon codewords
+// and interleaves the result.
+func addErrorCorrection(data []byte, layout blockLayout) []byte {
+	var blocks [][]byte
+	offset := 0
+	for i := 0; i < layout.group1+layout.group2; i++ {
+		n := layout.group1Data
+		if i >= layout.group1 {
+			n = layout.group2Data
+		}
+		blocks = append(blocks, data[offset:offset+n])
+		offset += n
+	}
+
+	generator := rsGenerator(layout.ecPerBlock)
+	ecBlocks := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		ecBlocks[i] = rsRemainder(block, generator)
+	}
+
+	var out []byte
+	maxData := layout.group1Data
+	if layout.group2Data > maxData {
+		maxData = layout.group2Data
+	}
+	for i := 0; i < maxData; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.Modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(version int) {
+	// Timing patterns
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	// Alignment patterns, skipping the three finder corners
+	centers := layouts[version].alignCenter
+	last := len(centers) - 1
+	for i, cy := range centers {
+		for j, cx := range centers {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; real bits are drawn once the mask is known
+	c.drawFormatBits(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>i)&1 == 1
+			a := c.Size - 11 + i%3
+			b := i / 3
+			c.set(a, b, dark)
+			c.set(b, a, dark)
+		}
+	}
+}
+
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.set(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormatBits writes both copies of the format information for level M.
+func (c *Code) drawFormatBits(mask int) {
+	data := mask // level M is encoded as 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true) // dark module
+}
+
+// drawCodewords fills the non-function modules in the standard zigzag order.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.Modules[y][x] = (codewords[i>>3]>>(7-i&7))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with the given mask pattern. Applying the
+// same mask twice restores the original modules.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				c.Modules[y][x] = !c.Modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol using the four rules of ISO/IEC 18004 section 7.8.3.
+func (c *Code) penalty() int {
+	score := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return c.Modules[x][y]
+		}
+		return c.Modules[y][x]
+	}
+
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < c.Size; y++ {
+			// Rule 1: runs of five or more modules of the same color
+			run := 1
+			for x := 1; x < c.Size; x++ {
+				if at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+
+			// Rule 3: finder-like 1:1:3:1:1 patterns next to four light modules
+			for x := 0; x+11 <= c.Size; x++ {
+				var pattern int
+				for k := 0; k < 11; k++ {
+					pattern <<= 1
+					if at(x+k, y, vertical) {
+						pattern |= 1
+					}
+				}
+				if pattern == 0x5D0 || pattern == 0x05D {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				v := c.Modules[y][x]
+				if v == c.Modules[y][x+1] && v == c.Modules[y+1][x] && v == c.Modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+
+	// Rule 4: balance of dark and light modules
+	total := c.Size * c.Size
+	deviation := abs(dark*100/total - 50)
+	score += deviation / 5 * 10
+
+	return score
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, (value>>i)&1 == 1)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	out := make([]byte, (len(b.bits)+7)/8)
+	for i, bit := range b.bits {
+		if bit {
+			out[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{1, 1},
+		{14, 1},
+		{15, 2},
+		{26, 2},
+		{27, 3},
+		{106, 6},
+		{107, 7},
+		{213, 10},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(strings.Repeat("a", tt.length))
+		if err != nil {
+			t.Fatalf("Encode of %d bytes: %v", tt.length, err)
+		}
+		if want := tt.version*4 + 17; code.Size != want {
+			t.Errorf("Encode of %d bytes: size %d, want %d (version %d)", tt.length, code.Size, want, tt.version)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 214)); err == nil {
+		t.Error("Encode accepted content beyond version 10")
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	// Byte mode 0100, count 5, "hello", terminator, then the 0xEC 0x11 pad
+	want := []byte{
+		0x40, 0x56, 0x86, 0x56, 0xC6, 0xC6, 0xF0,
+		0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC,
+	}
+	if got := encodeData([]byte("hello"), 1); !bytes.Equal(got, want) {
+		t.Errorf("encodeData = % X, want % X", got, want)
+	}
+}
+
+// gfExp returns alpha^n in GF(2^8), built independently of gfMultiply.
+func gfExp(n int) byte {
+	value := 1
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if value&0x100 != 0 {
+			value ^= 0x11D
+		}
+	}
+	return byte(value)
+}
+
+func TestRSGenerator(t *testing.T) {
+	// Exponents of alpha from ISO/IEC 18004 Annex A, leading term omitted
+	tests := []struct {
+		degree    int
+		exponents []int
+	}{
+		{7, []int{87, 229, 146, 149, 238, 102, 21}},
+		{10, []int{251, 67, 46, 61, 118, 70, 64, 94, 32, 45}},
+	}
+
+	for _, tt := range tests {
+		want := make([]byte, len(tt.exponents))
+		for i, exponent := range tt.exponents {
+			want[i] = gfExp(exponent)
+		}
+		if got := rsGenerator(tt.degree); !bytes.Equal(got, want) {
+			t.Errorf("rsGenerator(%d) = %v, want %v", tt.degree, got, want)
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" as version 1-M, the worked example used by most QR guides
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := rsRemainder(data, rsGenerator(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestGFMultiply(t *testing.T) {
+	for a := 0; a < 255; a++ {
+		for b := 0; b < 255; b += 17 {
+			if got, want := gfMultiply(gfExp(a), gfExp(b)), gfExp((a+b)%255); got != want {
+				t.Fatalf("gfMultiply(a^%d, a^%d) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+	if got := gfMultiply(0, 0x53); got != 0 {
+		t.Errorf("gfMultiply(0, x) = %d, want 0", got)
+	}
+}
+
+// readFormatBits reads both copies of the format information back from the
+// symbol, most significant bit first as the standard's tables list them.
+func readFormatBits(c *Code) (first, second int) {
+	var bits [15]bool
+	for i := 0; i <= 5; i++ {
+		bits[i] = c.Modules[i][8]
+	}
+	bits[6] = c.Modules[7][8]
+	bits[7] = c.Modules[8][8]
+	bits[8] = c.Modules[8][7]
+	for i := 9; i < 15; i++ {
+		bits[i] = c.Modules[8][14-i]
+	}
+	for i := 14; i >= 0; i-- {
+		first <<= 1
+		if bits[i] {
+			first |= 1
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		bits[i] = c.Modules[8][c.Size-1-i]
+	}
+	for i := 8; i < 15; i++ {
+		bits[i] = c.Modules[c.Size-15+i][8]
+	}
+	for i := 14; i >= 0; i-- {
+		second <<= 1
+		if bits[i] {
+			second |= 1
+		}
+	}
+	return first, second
+}
+
+func TestDrawFormatBits(t *testing.T) {
+	// Format information for level M, ISO/IEC 18004 Annex C
+	want := []int{
+		0x5412, // 101010000010010
+		0x5125, // 101000100100101
+		0x5E7C, // 101111001111100
+		0x5B4B, // 101101101001011
+		0x45F9, // 100010111111001
+		0x40CE, // 100000011001110
+		0x4F97, // 100111110010111
+		0x4AA0, // 100101010100000
+	}
+
+	for mask, bits := range want {
+		code := &Code{Size: 21, Modules: makeGrid(21), function: makeGrid(21)}
+		code.drawFormatBits(mask)
+
+		first, second := readFormatBits(code)
+		if first != bits || second != bits {
+			t.Errorf("mask %d: format bits %015b and %015b, want %015b", mask, first, second, bits)
+		}
+		if !code.Modules[code.Size-8][8] {
+			t.Errorf("mask %d: dark module is not set", mask)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	// Version information for version 7, ISO/IEC 18004 Annex D
+	const want = 0x07C94 // 000111110010010100
+
+	code, err := Encode(strings.Repeat("a", 110))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Size != 45 {
+		t.Fatalf("size %d, want 45 (version 7)", code.Size)
+	}
+
+	var upperRight, lowerLeft int
+	for i := 17; i >= 0; i-- {
+		a, b := code.Size-11+i%3, i/3
+		upperRight <<= 1
+		if code.Modules[b][a] {
+			upperRight |= 1
+		}
+		lowerLeft <<= 1
+		if code.Modules[a][b] {
+			lowerLeft |= 1
+		}
+	}
+	if upperRight != want || lowerLeft != want {
+		t.Errorf("version bits %018b and %018b, want %018b", upperRight, lowerLeft, want)
+	}
+}
+
+func TestEncodeFunctionPatterns(t *testing.T) {
+	code, err := Encode("otpauth://totp/eLibrary:alice?secret=GEZDGNBVGY3TQOJQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Finder pattern rows, top-left corner
+	finder := []string{"1111111", "1000001", "1011101", "1011101", "1011101", "1000001", "1111111"}
+	for y, row := range finder {
+		for x, module := range row {
+			if got := code.Modules[y][x]; got != (module == '1') {
+				t.Fatalf("finder module (%d, %d) = %v", x, y, got)
+			}
+		}
+	}
+
+	for i := 8; i < code.Size-8; i++ {
+		if code.Modules[6][i] != (i%2 == 0) || code.Modules[i][6] != (i%2 == 0) {
+			t.Fatalf("timing module %d is wrong", i)
+		}
+	}
+
+	// The chosen mask is one of the eight valid level M format words
+	first, second := readFormatBits(code)
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+	if first>>13 != 0x2 {
+		t.Errorf("format bits %015b do not encode level M", first)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	data, err := PNG("hello", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (21 + 2*quietZone) * 4; img.Bounds().Dx() != want || img.Bounds().Dy() != want {
+		t.Errorf("image is %v, want %dx%d", img.Bounds(), want, want)
+	}
+}
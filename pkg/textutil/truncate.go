@@ -0,0 +1,23 @@
+// Package textutil holds small string helpers shared by services that store
+// text from outside sources.
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Truncate makes s valid UTF-8, since text from outside can be in any
+// encoding, and cuts it to at most max bytes on a character boundary.
+func Truncate(s string, max int) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	if len(s) <= max {
+		return s
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS account_lockouts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL,
+    username VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    failed_count INT NOT NULL,
+    locked_until TIMESTAMP NOT NULL,
+    unlocked_at TIMESTAMP NULL,
+    unlocked_by INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account_lockouts_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (unlocked_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(255) NULL AFTER email_verified_at,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL AFTER totp_secret,
+    ADD COLUMN totp_last_step BIGINT NULL AFTER totp_enabled_at;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP NULL AFTER totp_last_step,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE AFTER deactivated_at;
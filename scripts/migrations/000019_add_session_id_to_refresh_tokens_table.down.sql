@@ -0,0 +1,3 @@
+ALTER TABLE refresh_tokens
+    DROP FOREIGN KEY fk_refresh_tokens_session_id,
+    DROP COLUMN session_id;
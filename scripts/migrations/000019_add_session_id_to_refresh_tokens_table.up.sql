@@ -0,0 +1,3 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN session_id INT NULL AFTER user_id,
+    ADD CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
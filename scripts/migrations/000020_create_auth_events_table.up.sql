@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL,
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    event_type VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_auth_events_created_at (created_at),
+    INDEX idx_auth_events_user_id (user_id, created_at),
+    INDEX idx_auth_events_ip_address (ip_address, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS book_copies (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    book_id INT NOT NULL,
+    barcode VARCHAR(64) NOT NULL UNIQUE,
+    acquired_at DATE NULL,
+    copy_condition VARCHAR(20) NOT NULL DEFAULT 'good',
+    shelf_location VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'available',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_book_copies_book_id_status (book_id, status),
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
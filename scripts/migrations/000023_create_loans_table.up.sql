@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS loans (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    copy_id INT NULL,
+    book_id INT NULL,
+    user_id INT NOT NULL,
+    barcode VARCHAR(64) NOT NULL,
+    checked_out_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_at TIMESTAMP NOT NULL,
+    returned_at TIMESTAMP NULL,
+    checked_out_by INT NULL,
+    returned_by INT NULL,
+    -- Equal to copy_id while the loan is active; the unique key keeps a copy
+    -- from being on two active loans
+    active_copy_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_loans_active_copy_id (active_copy_id),
+    INDEX idx_loans_user_id (user_id, checked_out_at),
+    INDEX idx_loans_book_id (book_id, checked_out_at),
+    INDEX idx_loans_copy_id (copy_id),
+    INDEX idx_loans_due_at (returned_at, due_at),
+    FOREIGN KEY (copy_id) REFERENCES book_copies(id) ON DELETE SET NULL,
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE SET NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (checked_out_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (returned_by) REFERENCES users(id) ON DELETE SET NULL
+);
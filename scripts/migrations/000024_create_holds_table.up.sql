@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS holds (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    book_id INT NOT NULL,
+    user_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    copy_id INT NULL,
+    ready_at TIMESTAMP NULL,
+    pickup_deadline TIMESTAMP NULL,
+    closed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- Set while the hold is waiting or ready, so a member holds a book only once
+    open_user_id INT AS (IF(status IN ('waiting', 'ready'), user_id, NULL)) STORED,
+    UNIQUE KEY uq_holds_open (book_id, open_user_id),
+    INDEX idx_holds_queue (book_id, status, id),
+    INDEX idx_holds_user_id (user_id, created_at),
+    INDEX idx_holds_pickup_deadline (status, pickup_deadline),
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (copy_id) REFERENCES book_copies(id) ON DELETE SET NULL
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS fines (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    loan_id INT NULL,
+    amount INT NOT NULL,
+    days_overdue INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'outstanding',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    resolved_at TIMESTAMP NULL,
+    resolved_by INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_fines_loan_id (loan_id),
+    INDEX idx_fines_user_id (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE SET NULL,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
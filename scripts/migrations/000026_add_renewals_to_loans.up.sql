@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    ADD COLUMN renewal_count INT NOT NULL DEFAULT 0 AFTER due_at,
+    ADD COLUMN last_renewed_at TIMESTAMP NULL AFTER renewal_count;
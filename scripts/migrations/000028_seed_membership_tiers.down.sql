@@ -0,0 +1 @@
+DELETE FROM membership_tiers WHERE name IN ('student', 'staff', 'public');
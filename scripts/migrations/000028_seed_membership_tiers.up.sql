@@ -0,0 +1,6 @@
+-- No tier is seeded as default, so members without a tier keep the loans
+-- settings from config until an admin picks a default tier
+INSERT INTO membership_tiers (name, description, max_loans, loan_period_days, hold_limit, is_default) VALUES
+    ('student', 'Students', 5, 14, 3, FALSE),
+    ('staff', 'Faculty and library staff', 10, 28, 5, FALSE),
+    ('public', 'Public members', 3, 14, 2, FALSE);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP FOREIGN KEY fk_users_tier_id,
+    DROP COLUMN tier_id;
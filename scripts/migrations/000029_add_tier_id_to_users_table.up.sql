@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN tier_id INT NULL AFTER role,
+    ADD CONSTRAINT fk_users_tier_id FOREIGN KEY (tier_id) REFERENCES membership_tiers(id);
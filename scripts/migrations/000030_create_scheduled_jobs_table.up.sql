@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    name VARCHAR(64) PRIMARY KEY,
+    schedule VARCHAR(100) NOT NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    -- The replica running the job holds the lock until locked_until, after
+    -- which another replica may take over a run that never finished
+    locked_by VARCHAR(128) NULL,
+    locked_until TIMESTAMP NULL,
+    last_started_at TIMESTAMP NULL,
+    last_finished_at TIMESTAMP NULL,
+    last_status VARCHAR(20) NULL,
+    last_error TEXT NULL,
+    last_result VARCHAR(255) NULL,
+    last_duration_ms INT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
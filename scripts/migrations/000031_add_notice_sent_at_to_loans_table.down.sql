@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    DROP COLUMN overdue_notice_sent_at,
+    DROP COLUMN reminder_sent_at;
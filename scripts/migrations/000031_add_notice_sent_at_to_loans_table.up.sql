@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    ADD COLUMN reminder_sent_at TIMESTAMP NULL AFTER last_renewed_at,
+    ADD COLUMN overdue_notice_sent_at TIMESTAMP NULL AFTER reminder_sent_at;
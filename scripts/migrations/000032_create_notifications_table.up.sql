@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    book_id INT NULL,
+    -- A notification the user only wants by email is kept out of the inbox
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    read_at TIMESTAMP NULL,
+    -- NULL when no email is wanted, otherwise pending, sent or failed
+    email_status VARCHAR(20) NULL,
+    email_attempts INT NOT NULL DEFAULT 0,
+    emailed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_user_id (user_id, in_app, created_at),
+    INDEX idx_notifications_email_status (email_status, id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE SET NULL
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS author_follows (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_author_follows_user_author (user_id, author),
+    INDEX idx_author_follows_author (author),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
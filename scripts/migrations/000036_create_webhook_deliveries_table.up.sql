@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    webhook_id INT NOT NULL,
+    -- Shared by every delivery and replay of the same event, so receivers
+    -- can drop duplicates
+    event_id CHAR(32) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload MEDIUMTEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+    last_attempt_at TIMESTAMP NULL,
+    response_status INT NULL,
+    response_body VARCHAR(1024) NULL,
+    last_error VARCHAR(1024) NULL,
+    delivered_at TIMESTAMP NULL,
+    -- The delivery this one replays; always of the same webhook
+    replay_of INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_webhook_deliveries_webhook_id (webhook_id, created_at),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    -- A delivery is replayed at most once
+    UNIQUE INDEX idx_webhook_deliveries_replay_of (replay_of),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS shelf_books (
+    shelf_id INT NOT NULL,
+    book_id INT NOT NULL,
+    position INT NOT NULL,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shelf_id, book_id),
+    INDEX idx_shelf_books_position (shelf_id, position),
+    INDEX idx_shelf_books_book_id (book_id),
+    FOREIGN KEY (shelf_id) REFERENCES shelves(id) ON DELETE CASCADE,
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);